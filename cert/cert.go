@@ -0,0 +1,238 @@
+// Package cert provides the certificates of the HTTPS server, either given as files or generated,
+// and generates client certificates for mutual TLS.
+package cert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const (
+	CAFile           = "ca.pem"
+	CAKeyFile        = "ca-key.pem"
+	CertFile         = "cert.pem"
+	KeyFile          = "key.pem"
+	caValidity       = 10 * 365 * 24 * time.Hour
+	leafValidity     = 365 * 24 * time.Hour
+	InvalidCA        = "Invalid CA"
+	IncompleteCA     = "Incomplete CA. Restore the missing file, or remove both to generate a new CA."
+	InvalidKeyPair   = "Invalid certificate or key"
+	FailedToGenerate = "Failed to generate certificate"
+)
+
+// Config returns the server TLS config of options, with HTTP/2 enabled. Without cert and key files a certificate
+// is generated into the directory of options first.
+func Config(options model.TLS) (*tls.Config, error) {
+	certFile, keyFile := options.CertFile, options.KeyFile
+	if len(certFile) == 0 {
+		var err error
+		if certFile, keyFile, err = Generate(options.Dir, options.Hosts); err != nil {
+			return nil, err
+		}
+	}
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s [%s]. %w", InvalidKeyPair, certFile, err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   tls.VersionTLS12,
+	}
+	switch options.ClientAuth {
+	case model.ClientAuthRequest:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case model.ClientAuthRequire:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return config, nil
+	}
+	config.ClientCAs, err = clientCAs(options)
+	return config, err
+}
+
+// clientCAs reads the CAs client certificates are verified against, by default the CA of dir.
+func clientCAs(options model.TLS) (*x509.CertPool, error) {
+	caFile := options.ClientCAFile
+	if len(caFile) == 0 {
+		if err := os.MkdirAll(options.Dir, 0o755); err != nil {
+			return nil, err
+		}
+		if _, _, err := loadOrCreateCA(options.Dir); err != nil {
+			return nil, err
+		}
+		caFile = filepath.Join(options.Dir, CAFile)
+	}
+	contents, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(contents) {
+		return nil, fmt.Errorf("%s [%s]", InvalidCA, caFile)
+	}
+	return pool, nil
+}
+
+// Generate writes a certificate and key for hosts, DNS names or IP addresses, into dir. It is signed by the CA in dir,
+// which is created if missing. The CA is kept across calls, while the certificate is replaced each time.
+func Generate(dir string, hosts []string) (string, string, error) {
+	template, err := templateOf(pkix.Name{CommonName: "Mockery"}, leafValidity)
+	if err != nil {
+		return "", "", err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	if err = addSANs(template, hosts); err != nil {
+		return "", "", err
+	}
+	return issue(dir, template, CertFile, KeyFile)
+}
+
+// GenerateClient writes a client certificate for commonName and sans into dir as '<name>.pem' and '<name>-key.pem',
+// signed by the CA in dir like the server certificate. SANs are DNS names, IP addresses, email addresses or URIs.
+func GenerateClient(dir string, name string, commonName string, sans []string) (string, string, error) {
+	template, err := templateOf(pkix.Name{CommonName: commonName}, leafValidity)
+	if err != nil {
+		return "", "", err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if err = addSANs(template, sans); err != nil {
+		return "", "", err
+	}
+	return issue(dir, template, name+".pem", name+"-key.pem")
+}
+
+func addSANs(template *x509.Certificate, sans []string) error {
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if strings.Contains(san, "://") {
+			uri, err := url.Parse(san)
+			if err != nil {
+				return err
+			}
+			template.URIs = append(template.URIs, uri)
+		} else if strings.Contains(san, "@") {
+			template.EmailAddresses = append(template.EmailAddresses, san)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+	return nil
+}
+
+// issue signs template with the CA in dir and writes the certificate and a new key into dir.
+func issue(dir string, template *x509.Certificate, certName string, keyName string) (string, string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+	ca, caKey, err := loadOrCreateCA(dir)
+	if err != nil {
+		return "", "", err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("%s. %w", FailedToGenerate, err)
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return "", "", fmt.Errorf("%s. %w", FailedToGenerate, err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, certName), filepath.Join(dir, keyName)
+	if err = writeKeyPair(certFile, der, keyFile, key); err != nil {
+		return "", "", err
+	}
+	return certFile, keyFile, nil
+}
+
+func loadOrCreateCA(dir string) (*x509.Certificate, crypto.Signer, error) {
+	caFile, caKeyFile := filepath.Join(dir, CAFile), filepath.Join(dir, CAKeyFile)
+	pair, err := tls.LoadX509KeyPair(caFile, caKeyFile)
+	if err == nil {
+		ca, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil || !ca.IsCA {
+			return nil, nil, fmt.Errorf("%s [%s]", InvalidCA, caFile)
+		}
+		signer, ok := pair.PrivateKey.(crypto.Signer)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s [%s]", InvalidCA, caKeyFile)
+		}
+		return ca, signer, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("%s [%s]. %w", InvalidCA, caFile, err)
+	}
+	// A new CA is only generated without both files, so a lost key never silently replaces a trusted CA.
+	for _, file := range []string{caFile, caKeyFile} {
+		if _, err := os.Stat(file); !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%s [%s]", IncompleteCA, file)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s. %w", FailedToGenerate, err)
+	}
+	template, err := templateOf(pkix.Name{CommonName: "Mockery CA", Organization: []string{"Mockery"}}, caValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s. %w", FailedToGenerate, err)
+	}
+	if err = writeKeyPair(caFile, der, caKeyFile, key); err != nil {
+		return nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	return ca, key, err
+}
+
+func templateOf(subject pkix.Name, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("%s. %w", FailedToGenerate, err)
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+// writeKeyPair writes a DER certificate and its key as PEM files. The key is readable by the owner only.
+func writeKeyPair(certFile string, der []byte, keyFile string, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+}
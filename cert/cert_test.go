@@ -0,0 +1,122 @@
+package cert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func readCertificate(t *testing.T, file string) *x509.Certificate {
+	t.Helper()
+	contents, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		t.Fatalf("Expected PEM certificate in %s", file)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	return certificate
+}
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tls")
+	certFile, keyFile, err := Generate(dir, []string{"localhost", "127.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, CertFile), certFile)
+	assert.Equal(t, filepath.Join(dir, KeyFile), keyFile)
+
+	ca := readCertificate(t, filepath.Join(dir, CAFile))
+	assert.True(t, ca.IsCA)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	leaf := readCertificate(t, certFile)
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		_, err = leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		assert.NoError(t, err, host)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: roots})
+	assert.Error(t, err)
+
+	info, err := os.Stat(keyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, _, err = Generate(dir, []string{"mockery.local"})
+	assert.NoError(t, err)
+	assert.Equal(t, ca.Raw, readCertificate(t, filepath.Join(dir, CAFile)).Raw)
+	assert.Equal(t, []string{"mockery.local"}, readCertificate(t, certFile).DNSNames)
+}
+
+func TestGenerateClient(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, err := GenerateClient(dir, "billing", "billing-service", []string{"billing.local", "10.0.0.1", "billing@example.com", "spiffe://example.com/billing"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "billing.pem"), certFile)
+	assert.Equal(t, filepath.Join(dir, "billing-key.pem"), keyFile)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(readCertificate(t, filepath.Join(dir, CAFile)))
+	client := readCertificate(t, certFile)
+	_, err = client.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ClientCert{
+		CommonName: "billing-service",
+		SANs:       []string{"billing.local", "billing@example.com", "10.0.0.1", "spiffe://example.com/billing"},
+		Issuer:     "Mockery CA",
+	}, model.ClientCertOf(client))
+}
+
+func TestGenerate_InvalidCA(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, CAFile), []byte("invalid"), 0o644)
+	os.WriteFile(filepath.Join(dir, CAKeyFile), []byte("invalid"), 0o600)
+	_, _, err := Generate(dir, []string{"localhost"})
+	assert.ErrorContains(t, err, InvalidCA)
+}
+
+func TestGenerate_IncompleteCA(t *testing.T) {
+	for _, missing := range []string{CAFile, CAKeyFile} {
+		dir := t.TempDir()
+		_, _, err := Generate(dir, []string{"localhost"})
+		assert.NoError(t, err)
+		ca, _ := os.ReadFile(filepath.Join(dir, CAFile))
+		os.Remove(filepath.Join(dir, missing))
+
+		_, _, err = Generate(dir, []string{"localhost"})
+		assert.ErrorContains(t, err, IncompleteCA)
+		if missing == CAKeyFile {
+			kept, _ := os.ReadFile(filepath.Join(dir, CAFile))
+			assert.Equal(t, ca, kept)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	dir := t.TempDir()
+	config, err := Config(model.TLS{Dir: dir, Hosts: []string{"localhost"}})
+	assert.NoError(t, err)
+	assert.Len(t, config.Certificates, 1)
+	assert.Contains(t, config.NextProtos, "h2")
+
+	config, err = Config(model.TLS{CertFile: filepath.Join(dir, CertFile), KeyFile: filepath.Join(dir, KeyFile)})
+	assert.NoError(t, err)
+	assert.Len(t, config.Certificates, 1)
+
+	_, err = Config(model.TLS{CertFile: filepath.Join(dir, CertFile), KeyFile: filepath.Join(dir, CAKeyFile)})
+	assert.ErrorContains(t, err, InvalidKeyPair)
+
+	config, err = Config(model.TLS{Dir: dir, Hosts: []string{"localhost"}, ClientAuth: model.ClientAuthRequire})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.NotNil(t, config.ClientCAs)
+
+	_, err = Config(model.TLS{Dir: dir, Hosts: []string{"localhost"}, ClientAuth: model.ClientAuthRequest, ClientCAFile: filepath.Join(dir, KeyFile)})
+	assert.ErrorContains(t, err, InvalidCA)
+}
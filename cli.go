@@ -0,0 +1,738 @@
+package main
+
+import (
+	"cmp"
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rromanowicz/mockery/cert"
+	"github.com/rromanowicz/mockery/client"
+	"github.com/rromanowicz/mockery/context"
+	"github.com/rromanowicz/mockery/lint"
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/server"
+	"github.com/rromanowicz/mockery/service"
+	"github.com/rromanowicz/mockery/util"
+)
+
+// Exit codes of all subcommands.
+const (
+	exitOK = 0
+	// exitFailed is returned for invalid mocks, failed imports, rejected API calls and I/O errors.
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+// command is a subcommand of mockery. Its flag set is created by run, so all commands print their usage alike.
+type command struct {
+	name    string
+	group   string
+	summary string
+	// args follow the command name in its usage line.
+	args string
+	// help is printed between the usage line and the flags.
+	help string
+	run  func(flags *flag.FlagSet, args []string) int
+}
+
+const (
+	groupServer   = "Server"
+	groupFiles    = "Mock files"
+	groupDatabase = "Database (without starting the server)"
+	groupRunning  = "Running server"
+)
+
+// commands are listed by printUsage in this order.
+var commands = []command{
+	{name: "serve", group: groupServer, summary: "Start the mock server (default command)", args: "[flags]", run: runServe},
+	{name: "cert", group: groupServer, summary: "Generate a client certificate signed by the CA of the server", args: "-cn name [-san names] [-dir dir] [-o name]", run: runCert},
+	{name: "validate", group: groupFiles, summary: "Validate mock files", args: "[-folders tag|namespace|none] <file or directory>...", run: runValidate},
+	{name: "lint", group: groupFiles, summary: "Find duplicate, shadowed and unreachable mocks in files or the database", args: "[flags] [file or directory]...", run: runLint},
+	{name: "snippet", group: groupFiles, summary: "Print curl commands or Go code for mocks", args: "[-format curl|go] [-url base] <file or directory>...", run: runSnippet},
+	{name: "openapi", group: groupFiles, summary: "Convert an OpenAPI 3 document into mock files", args: "[-paths regex|example] [-o file] <document>", run: runOpenAPI},
+	{name: "postman", group: groupFiles, summary: "Convert a Postman v2.1 collection into mock files", args: "[-o file] <collection>", run: runPostman},
+	{name: "posting", group: groupFiles, summary: "Convert Posting request files into mock files", args: "[-o file] <file or directory>...", run: runPosting},
+	{name: "har", group: groupFiles, summary: "Convert a HAR file into mock files", args: "[-o file] [-body=false] [-query=false] [-headers names] <file>", run: runHAR},
+	{name: "wiremock", group: groupFiles, summary: "Convert WireMock mappings into mock files, or back with -export", args: "[-o file] [-export] <file or directory>...", run: runWireMock},
+	{name: "pact", group: groupFiles, summary: "Convert pact files into mock files", args: "[-o file] <pact file>...", run: runPact},
+	{name: "import", group: groupDatabase, summary: "Import mock files", args: "[flags] <file or directory>...", run: runImport},
+	{name: "export", group: groupDatabase, summary: "Export mocks to files", args: "[flags]", run: runExport},
+	{name: "list", group: groupDatabase, summary: "List mocks", args: "[flags]", run: runList},
+	{name: "ctl", group: groupRunning, summary: "Call the admin API", args: ctlArgs, help: ctlCommands, run: runCtl},
+}
+
+// run dispatches args to their command, serve if they start with a flag.
+func run(args []string) int {
+	name := "serve"
+	if len(args) != 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		printUsage()
+		return exitOK
+	}
+	index := slices.IndexFunc(commands, func(cmd command) bool { return cmd.name == name })
+	if index < 0 {
+		fmt.Fprintf(os.Stderr, "Unknown command [%s]\n\n", name)
+		printUsage()
+		return exitUsage
+	}
+	cmd := commands[index]
+	flags := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: mockery %s %s\n", cmd.name, cmd.args)
+		if len(cmd.help) != 0 {
+			fmt.Fprintf(flags.Output(), "\n%s\n", cmd.help)
+		}
+		flags.PrintDefaults()
+	}
+	return cmd.run(flags, args)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: mockery [command] [flags]")
+	var group string
+	for _, cmd := range commands {
+		if cmd.group != group {
+			group = cmd.group
+			fmt.Fprintf(os.Stderr, "\n%s:\n", group)
+		}
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'mockery <command> -h' for the flags of a command.")
+}
+
+// runServe starts the server with the config file, environment and flag overrides.
+func runServe(flags *flag.FlagSet, args []string) int {
+	db := addDBFlags(flags)
+	serverPort := flags.Int("port", 0, "Server port.")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	config, err := db.read(*serverPort)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if err := server.StartMockServer(&config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// runCert generates a client certificate for mutual TLS, signed by the CA of the TLS directory.
+func runCert(flags *flag.FlagSet, args []string) int {
+	dir := flags.String("dir", model.TLSDir, "TLS directory of the server, holding its CA.")
+	commonName := flags.String("cn", "", "Subject common name.")
+	sans := flags.String("san", "", "Comma separated SANs: DNS names, IP addresses, emails or URIs.")
+	name := flags.String("o", "client", "File name, written as <name>.pem and <name>-key.pem.")
+	flags.Parse(args)
+	if flags.NArg() != 0 || len(*commonName) == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var names []string
+	if len(*sans) != 0 {
+		names = strings.Split(*sans, ",")
+	}
+	certFile, keyFile, err := cert.GenerateClient(*dir, *name, *commonName, names)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	fmt.Println(certFile)
+	fmt.Println(keyFile)
+	return exitOK
+}
+
+// runValidate validates mock files, and mock directories with their folder defaults, without a database.
+func runValidate(flags *flag.FlagSet, args []string) int {
+	folders := flags.String("folders", model.FoldersAsTags, "Folders of directories as 'tag', 'namespace' or 'none'.")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	results, err := readMockResults(flags.Args(), model.ImportOptions{Folders: *folders})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	valid := 0
+	for _, result := range results {
+		if result.Status == model.ImportOK {
+			valid++
+		}
+	}
+	code := printFailures(results)
+	fmt.Printf("%d valid, %d invalid\n", valid, len(results)-valid)
+	return code
+}
+
+// runLint checks mock files, or all mocks of the database without arguments, for duplicate, shadowed and unreachable mocks.
+func runLint(flags *flag.FlagSet, args []string) int {
+	db := addDBFlags(flags)
+	asJSON := flags.Bool("json", false, "Print issues as JSON.")
+	flags.Parse(args)
+
+	var mocks []model.Mock
+	// Mocks of files have no ids, they are numbered in file order and reported by file.
+	files := map[int64]string{}
+	code := exitOK
+	if flags.NArg() != 0 {
+		results, err := readMockResults(flags.Args(), model.ImportOptions{Folders: model.FoldersAsTags})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+		code = printFailures(results)
+		for _, result := range results {
+			if result.Status == model.ImportOK {
+				mock := *result.Mock
+				mock.ID = int64(len(mocks) + 1)
+				files[mock.ID] = result.File
+				mocks = append(mocks, mock)
+			}
+		}
+	} else {
+		mockService, _, err := db.open()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+		defer mockService.Repository.CloseDB()
+		if mocks, err = mockService.Repository.GetAll(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+	}
+
+	issues := lint.Mocks(mocks)
+	if *asJSON {
+		printJSON(issues)
+	} else {
+		printIssues(issues, files)
+	}
+	if len(issues) != 0 {
+		return exitFailed
+	}
+	return code
+}
+
+// printIssues lists issues by mock id, or by file and position of mocks read from files.
+func printIssues(issues []lint.Issue, files map[int64]string) {
+	name := func(id int64) string {
+		if file, ok := files[id]; ok {
+			return fmt.Sprintf("%s (mock %d)", file, id)
+		}
+		return fmt.Sprintf("mock %d", id)
+	}
+	for _, issue := range issues {
+		fmt.Printf("%s %s %s %s: %s", issue.Rule, name(issue.MockID), issue.Method, issue.Path, issue.Message)
+		if issue.RelatedID != 0 {
+			fmt.Printf(" [%s]", name(issue.RelatedID))
+		}
+		fmt.Println()
+	}
+}
+
+// readMockResults reads mock files, and mock directories with their folder defaults. Mocks of a single file
+// are sourced from its name, as if it was in the import directory. Failures are reported by the commands, the import log would repeat them, so logging is muted while reading.
+func readMockResults(paths []string, options model.ImportOptions) ([]model.ImportResult, error) {
+	output := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(output)
+	var results []model.ImportResult
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			dirResults, err := util.Import(path, options)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, dirResults...)
+			continue
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileResults := util.ReadMocks(path, contents, model.ImportDefaults{})
+		for i := range fileResults {
+			if fileResults[i].Mock != nil {
+				fileResults[i].Mock.Source = filepath.Base(path)
+			}
+		}
+		results = append(results, fileResults...)
+	}
+	return results, nil
+}
+
+// singleFiles returns the sources of paths if none of them is a directory, so a sync import of files
+// deletes only mocks imported from them.
+func singleFiles(paths []string) []string {
+	var files []string
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			return nil
+		}
+		files = append(files, filepath.Base(path))
+	}
+	return files
+}
+
+// printFailures reports failed results on stderr and returns exitFailed if there are any.
+func printFailures(results []model.ImportResult) int {
+	code := exitOK
+	for _, result := range results {
+		if result.Status != model.ImportOK {
+			fmt.Fprintf(os.Stderr, "%s %s %v\n", result.Status, location(result), result.Errors)
+			code = exitFailed
+		}
+	}
+	return code
+}
+
+// location names the file of a result, or the natural key of mocks deleted by a sync import. Errors carry their line.
+func location(result model.ImportResult) string {
+	return cmp.Or(result.File, result.Key)
+}
+
+// printResults lists import results with their action and returns exitFailed if any of them failed.
+func printResults(results []model.ImportResult) int {
+	for _, result := range results {
+		if result.Status == model.ImportOK {
+			fmt.Printf("%s %s %s\n", result.Status, result.Action, location(result))
+		}
+	}
+	return printFailures(results)
+}
+
+// dbFlags select the database of the import, export and list commands.
+type dbFlags struct {
+	config  *string
+	dbType  *string
+	connStr *string
+}
+
+func addDBFlags(flags *flag.FlagSet) dbFlags {
+	return dbFlags{
+		config:  flags.String("config", cmp.Or(os.Getenv("MOCKERY_CONFIG"), server.ConfigFilePath), "Config file, or MOCKERY_CONFIG."),
+		dbType:  flags.String("db", "", "Database type (InMemory, SqLite or Postgres)."),
+		connStr: flags.String("conn", "", "Database connection string."),
+	}
+}
+
+// read layers the config file, environment and flags into a validated config.
+func (f dbFlags) read(port int) (model.Config, error) {
+	return server.ReadConfig(*f.config, server.Overrides{Port: port, DBType: *f.dbType, ConnStr: *f.connStr})
+}
+
+// open connects to the database of the config file, overridden by environment and flags.
+// The in-memory database is rejected, as it is gone once the command completes.
+func (f dbFlags) open() (service.MockService, model.Config, error) {
+	config, err := f.read(0)
+	if err != nil {
+		return service.MockService{}, config, err
+	}
+	if config.DBType == model.InMemory {
+		return service.MockService{}, config, fmt.Errorf("[%s] - %s", config.DBType, "In-memory database can not be used from the command line. Configure SqLite or Postgres.")
+	}
+	mockService, err := context.InitMockService(&config)
+	return mockService, config, err
+}
+
+// selectorFlags select mocks like the selector query parameters of the admin API.
+type selectorFlags struct {
+	id   *int64
+	name *string
+	tags *string
+}
+
+func addSelectorFlags(flags *flag.FlagSet) selectorFlags {
+	return selectorFlags{
+		id:   flags.Int64("id", 0, "Mock id."),
+		name: flags.String("name", "", "Mock name."),
+		tags: flags.String("tag", "", "Comma separated mock tags, all must be present."),
+	}
+}
+
+func (f selectorFlags) selector(namespace string) model.Selector {
+	selector := model.Selector{Namespace: namespace, ID: *f.id, Name: *f.name}
+	for tag := range strings.SplitSeq(*f.tags, ",") {
+		if tag = strings.TrimSpace(tag); len(tag) != 0 {
+			selector.Tags = append(selector.Tags, tag)
+		}
+	}
+	return selector
+}
+
+// runImport imports mock files into the database without starting the server.
+func runImport(flags *flag.FlagSet, args []string) int {
+	db := addDBFlags(flags)
+	namespace := flags.String("namespace", "", "Namespace of mocks without one.")
+	folders := flags.String("folders", "", "Folders of directories as 'tag', 'namespace' or 'none'. Defaults to 'importFolders'.")
+	mode := flags.String("mode", "", "Import mode 'create', 'upsert' or 'sync'. Defaults to 'importMode'.")
+	dryRun := flags.Bool("dry-run", false, "Report the changes without saving them.")
+	flags.Parse(args)
+	if flags.NArg() == 0 || (len(*mode) != 0 && !model.IsValidImportMode(*mode)) {
+		flags.Usage()
+		return exitUsage
+	}
+
+	mockService, config, err := db.open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	defer mockService.Repository.CloseDB()
+	options := model.ImportOptions{Namespace: *namespace, Folders: cmp.Or(*folders, config.ImportFolders), Mode: *mode, DryRun: *dryRun, Files: singleFiles(flags.Args())}
+	results, err := readMockResults(flags.Args(), options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if results, err = mockService.ImportResults(results, options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return printResults(results)
+}
+
+// runExport writes the selected mocks of the database to files without starting the server.
+func runExport(flags *flag.FlagSet, args []string) int {
+	db := addDBFlags(flags)
+	selector := addSelectorFlags(flags)
+	namespace := flags.String("namespace", "", "Namespace of the mocks.")
+	format := flags.String("format", model.FormatJSON, "File format (json or yaml).")
+	out := flags.String("o", "", "Output directory. Defaults to 'exportDir'.")
+	flags.Parse(args)
+	if flags.NArg() != 0 || (*format != model.FormatJSON && *format != model.FormatYAML) {
+		flags.Usage()
+		return exitUsage
+	}
+
+	mockService, config, err := db.open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	defer mockService.Repository.CloseDB()
+	mocks, err := mockService.List(selector.selector(*namespace))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	files, err := util.Export(cmp.Or(*out, config.ExportDir), mocks, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	return exitOK
+}
+
+// runList prints the selected mocks of the database.
+func runList(flags *flag.FlagSet, args []string) int {
+	db := addDBFlags(flags)
+	selector := addSelectorFlags(flags)
+	namespace := flags.String("namespace", "", "Namespace of the mocks.")
+	asJSON := flags.Bool("json", false, "Print mocks as JSON.")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	mockService, _, err := db.open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	defer mockService.Repository.CloseDB()
+	mocks, err := mockService.List(selector.selector(*namespace))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	printMocks(mocks, *asJSON)
+	return exitOK
+}
+
+func printMocks(mocks []model.Mock, asJSON bool) {
+	if asJSON {
+		printJSON(mocks)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tMETHOD\tPATH\tSTATUS\tNAME\tTAGS\tDISABLED")
+	for _, mock := range mocks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\t%t\n", mock.ID, mock.Method, mock.Path+mock.RegexPath, mock.ResponseStatus, mock.Name, strings.Join(mock.Tags, ","), mock.Disabled)
+	}
+	w.Flush()
+}
+
+func printJSON(value any) {
+	data, _ := json.MarshalIndent(value, "", "  ")
+	os.Stdout.Write(append(data, '\n'))
+}
+
+const (
+	ctlArgs     = "[-url base] [-namespace ns] [-session id] <command> [flags] [args]"
+	ctlCommands = `Commands:
+  health                   Check the server is up
+  list [selector] [-json]  List mocks
+  create <file>...         Create the mocks of mock files
+  delete <selector>        Delete mocks
+  enable <selector>        Enable mocks
+  disable <selector>       Disable mocks
+  reset                    Delete all mocks of the namespace
+  import [flags] [dir]     Import a subdirectory of the import directory
+  export [flags] [dir]     Export to a subdirectory of the export directory
+  journal [-clear]         Print or clear the request journal
+  lint [-json]             Find duplicate, shadowed and unreachable mocks of the namespace
+  match [flags] <url>      Show which mock would answer a request, without sending it
+  settings                 Print the effective config of the server
+
+Selector flags: -id, -name and -tag.`
+	ctlUsage = "Usage: mockery ctl " + ctlArgs + "\n\n" + ctlCommands + "\n"
+)
+
+// runCtl calls the admin API of a running server.
+func runCtl(flags *flag.FlagSet, args []string) int {
+	baseURL := flags.String("url", cmp.Or(os.Getenv("MOCKERY_URL"), "http://localhost:8080"), "Base url of the server, or MOCKERY_URL.")
+	namespace := flags.String("namespace", "", "Namespace.")
+	session := flags.String("session", "", "Test session id.")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	c := client.New(*baseURL).InNamespace(*namespace).InSession(*session)
+	code, err := ctl(c, flags.Arg(0), flags.Args()[1:])
+	var apiErr *client.Error
+	switch {
+	case errors.As(err, &apiErr) && len(apiErr.ValidationErrors) != 0:
+		fmt.Fprintf(os.Stderr, "%d %v\n", apiErr.StatusCode, apiErr.ValidationErrors)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+func ctl(c *client.Client, command string, args []string) (int, error) {
+	ctx := gocontext.Background()
+	flags := flag.NewFlagSet("ctl "+command, flag.ExitOnError)
+	selector := addSelectorFlags(flags)
+	failed := func(err error) (int, error) {
+		if err != nil {
+			return exitFailed, err
+		}
+		return exitOK, nil
+	}
+
+	switch command {
+	case "health":
+		return failed(c.Health(ctx))
+	case "list":
+		asJSON := flags.Bool("json", false, "Print mocks as JSON.")
+		flags.Parse(args)
+		mocks, err := c.List(ctx, selector.selector(""))
+		if err == nil {
+			printMocks(mocks, *asJSON)
+		}
+		return failed(err)
+	case "create":
+		flags.Parse(args)
+		if flags.NArg() == 0 {
+			return exitUsage, errors.New(ctlUsage)
+		}
+		results, err := readMockResults(flags.Args(), model.ImportOptions{Folders: model.FoldersIgnored})
+		if err != nil {
+			return exitFailed, err
+		}
+		code := printFailures(results)
+		for _, result := range results {
+			if result.Status != model.ImportOK {
+				continue
+			}
+			mock, err := c.Create(ctx, *result.Mock)
+			if err != nil {
+				return exitFailed, fmt.Errorf("%s: %w", location(result), err)
+			}
+			fmt.Printf("%d %s %s\n", mock.ID, mock.Method, mock.Path+mock.RegexPath)
+		}
+		return code, nil
+	case "delete", "enable", "disable":
+		flags.Parse(args)
+		selected := selector.selector("")
+		if selected.IsEmpty() {
+			return exitUsage, errors.New("Missing selector. Provide -id, -name or -tag.")
+		}
+		var count int
+		var err error
+		switch command {
+		case "delete":
+			if len(selected.Name) == 0 && len(selected.Tags) == 0 {
+				if err = c.Delete(ctx, selected.ID); err == nil {
+					count = 1
+				}
+			} else {
+				count, err = c.DeleteAll(ctx, selected)
+			}
+		case "enable":
+			count, err = c.Enable(ctx, selected)
+		case "disable":
+			count, err = c.Disable(ctx, selected)
+		}
+		if err == nil {
+			fmt.Printf("%s %d\n", command, count)
+		}
+		return failed(err)
+	case "reset":
+		count, err := c.Reset(ctx)
+		if err == nil {
+			fmt.Printf("reset %d\n", count)
+		}
+		return failed(err)
+	case "import":
+		folders := flags.String("folders", "", "Folders as 'tag', 'namespace' or 'none'.")
+		mode := flags.String("mode", "", "Import mode 'create', 'upsert' or 'sync'.")
+		dryRun := flags.Bool("dry-run", false, "Report the changes without saving them.")
+		flags.Parse(args)
+		results, err := c.Import(ctx, flags.Arg(0), model.ImportOptions{Folders: *folders, Mode: *mode, DryRun: *dryRun})
+		if err != nil {
+			return exitFailed, err
+		}
+		return printResults(results), nil
+	case "export":
+		format := flags.String("format", "", "File format (json or yaml).")
+		flags.Parse(args)
+		files, err := c.Export(ctx, flags.Arg(0), *format, selector.selector(""))
+		for _, file := range files {
+			fmt.Println(file)
+		}
+		return failed(err)
+	case "journal":
+		clearJournal := flags.Bool("clear", false, "Clear the journal.")
+		flags.Parse(args)
+		if *clearJournal {
+			count, err := c.ClearJournal(ctx)
+			if err == nil {
+				fmt.Printf("cleared %d\n", count)
+			}
+			return failed(err)
+		}
+		entries, err := c.Journal(ctx)
+		if err == nil {
+			printJSON(entries)
+		}
+		return failed(err)
+	case "lint":
+		asJSON := flags.Bool("json", false, "Print issues as JSON.")
+		flags.Parse(args)
+		issues, err := c.Lint(ctx)
+		if err != nil {
+			return exitFailed, err
+		}
+		if *asJSON {
+			printJSON(issues)
+		} else {
+			printIssues(issues, nil)
+		}
+		if len(issues) != 0 {
+			return exitFailed, nil
+		}
+		return exitOK, nil
+	case "settings":
+		config, err := c.Settings(ctx)
+		if err == nil {
+			printJSON(config)
+		}
+		return failed(err)
+	case "match":
+		method := flags.String("X", http.MethodGet, "Request method.")
+		headers := headerFlags{}
+		flags.Var(headers, "H", "Request header as 'Key: value', repeatable.")
+		body := flags.String("d", "", "Request body.")
+		asJSON := flags.Bool("json", false, "Print the report as JSON.")
+		flags.Parse(args)
+		if flags.NArg() != 1 {
+			return exitUsage, errors.New(ctlUsage)
+		}
+		report, err := c.Match(ctx, model.JournalRequest{Method: *method, URL: flags.Arg(0), Headers: http.Header(headers), Body: *body})
+		if err != nil {
+			return exitFailed, err
+		}
+		if *asJSON {
+			printJSON(report)
+		} else {
+			printMatch(report)
+		}
+		if report.Mock == nil {
+			return exitFailed, nil
+		}
+		return exitOK, nil
+	}
+	return exitUsage, fmt.Errorf("Unknown command [%s]\n\n%s", command, ctlUsage)
+}
+
+// headerFlags collects repeated 'Key: value' flags.
+type headerFlags http.Header
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(http.Header(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	key, headerValue, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("Invalid header [%s]. Expected 'Key: value'.", value)
+	}
+	http.Header(h).Add(strings.TrimSpace(key), strings.TrimSpace(headerValue))
+	return nil
+}
+
+func printMatch(report model.MatchReport) {
+	if report.Mock != nil {
+		fmt.Printf("%s %s: mock %d %s\n", report.Method, report.Path, report.Mock.ID, report.Mock.Name)
+	} else {
+		fmt.Printf("%s %s: %s\n", report.Method, report.Path, report.Message)
+	}
+	if report.Contract != nil && !report.Contract.Valid {
+		fmt.Printf("  contract: %s\n", strings.Join(report.Contract.Errors, "; "))
+	}
+	for _, candidate := range report.Candidates {
+		state := "not matched"
+		if candidate.Matched {
+			state = "matched"
+		}
+		fmt.Printf("  mock %d %s %s\n", candidate.Mock.ID, candidate.Mock.Path+candidate.Mock.RegexPath, state)
+		for _, matcher := range candidate.Matchers {
+			fmt.Printf("    %s %s=%v %v %t\n", matcher.Type, matcher.Key, matcher.Expected, matcher.Actual, matcher.Matched)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+// Package client calls the admin API of a running mock server.
+//
+//	c := client.New("http://localhost:8080")
+//	mocks, err := c.Stub(ctx, client.Mock().Get("/persons").WithQuery("id", "1").Returns(200, `{"name": "John"}`))
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rromanowicz/mockery/har"
+	"github.com/rromanowicz/mockery/lint"
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/openapi"
+	"github.com/rromanowicz/mockery/pact"
+	"github.com/rromanowicz/mockery/snippet"
+	"github.com/rromanowicz/mockery/wiremock"
+)
+
+var (
+	ErrNotFound    = errors.New("Not found")
+	ErrInvalidMock = errors.New("Invalid mock")
+)
+
+// Error is a response of the server with an unexpected status.
+// It matches ErrNotFound for 404 responses and ErrInvalidMock for rejected mocks.
+type Error struct {
+	StatusCode int
+	Message    string
+	// ValidationErrors lists the validation messages of a rejected mock.
+	ValidationErrors []string
+}
+
+func (e *Error) Error() string {
+	if len(e.ValidationErrors) != 0 {
+		return fmt.Sprintf("%d %s", e.StatusCode, strings.Join(e.ValidationErrors, " "))
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrInvalidMock:
+		return len(e.ValidationErrors) != 0
+	}
+	return false
+}
+
+// Client sends requests to the admin API within a namespace and, optionally, a session.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Namespace is sent in NamespaceHeader, the default namespace is used if empty.
+	Namespace       string
+	NamespaceHeader string
+	SessionID       string
+}
+
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:         strings.TrimSuffix(baseURL, "/"),
+		HTTPClient:      http.DefaultClient,
+		NamespaceHeader: model.DefaultNamespaceHeader,
+	}
+}
+
+// InNamespace returns a copy of the client working in namespace.
+func (c *Client) InNamespace(namespace string) *Client {
+	scoped := *c
+	scoped.Namespace = namespace
+	return &scoped
+}
+
+// InSession returns a copy of the client working in the test session id.
+func (c *Client) InSession(id string) *Client {
+	scoped := *c
+	scoped.SessionID = id
+	return &scoped
+}
+
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil, "", nil)
+}
+
+// Create stores mock and returns it with its id.
+func (c *Client) Create(ctx context.Context, mock model.Mock) (model.Mock, error) {
+	body, err := json.Marshal(mock)
+	if err != nil {
+		return model.Mock{}, err
+	}
+	var created model.Mock
+	err = c.do(ctx, http.MethodPost, "/config", nil, bytes.NewReader(body), "application/json", &created)
+	return created, err
+}
+
+// Stub builds and creates mocks, stopping at the first error.
+func (c *Client) Stub(ctx context.Context, mocks ...*MockBuilder) ([]model.Mock, error) {
+	created := make([]model.Mock, 0, len(mocks))
+	for _, builder := range mocks {
+		mock, err := builder.Build()
+		if err != nil {
+			return created, err
+		}
+		if mock, err = c.Create(ctx, mock); err != nil {
+			return created, err
+		}
+		created = append(created, mock)
+	}
+	return created, nil
+}
+
+func (c *Client) List(ctx context.Context, selector model.Selector) ([]model.Mock, error) {
+	var mocks []model.Mock
+	err := c.do(ctx, http.MethodGet, "/config/list", selectorQuery(selector), nil, "", &mocks)
+	return mocks, err
+}
+
+func (c *Client) Delete(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, "/config", selectorQuery(model.Selector{ID: id}), nil, "", nil)
+}
+
+// DeleteAll removes the selected mocks and returns their number. The selector can not be empty.
+func (c *Client) DeleteAll(ctx context.Context, selector model.Selector) (int, error) {
+	return c.count(ctx, http.MethodDelete, "/config", selectorQuery(selector))
+}
+
+func (c *Client) Enable(ctx context.Context, selector model.Selector) (int, error) {
+	return c.count(ctx, http.MethodPost, "/config/enable", selectorQuery(selector))
+}
+
+func (c *Client) Disable(ctx context.Context, selector model.Selector) (int, error) {
+	return c.count(ctx, http.MethodPost, "/config/disable", selectorQuery(selector))
+}
+
+// Reset removes all mocks of the namespace.
+func (c *Client) Reset(ctx context.Context) (int, error) {
+	return c.count(ctx, http.MethodDelete, "/config/reset", nil)
+}
+
+func (c *Client) Snippets(ctx context.Context, selector model.Selector) ([]snippet.Snippet, error) {
+	var snippets []snippet.Snippet
+	err := c.do(ctx, http.MethodGet, "/config/snippets", selectorQuery(selector), nil, "", &snippets)
+	return snippets, err
+}
+
+// Lint reports duplicate, shadowed and unreachable mocks of the namespace.
+func (c *Client) Lint(ctx context.Context) ([]lint.Issue, error) {
+	var issues []lint.Issue
+	err := c.do(ctx, http.MethodGet, "/config/lint", nil, nil, "", &issues)
+	return issues, err
+}
+
+// Settings returns the effective config of the server, with connection strings redacted.
+func (c *Client) Settings(ctx context.Context) (model.Config, error) {
+	var config model.Config
+	err := c.do(ctx, http.MethodGet, "/config/settings", nil, nil, "", &config)
+	return config, err
+}
+
+// Match reports which mock would answer request, without sending it to the mocks.
+func (c *Client) Match(ctx context.Context, request model.JournalRequest) (model.MatchReport, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return model.MatchReport{}, err
+	}
+	var report model.MatchReport
+	err = c.do(ctx, http.MethodPost, "/config/match", nil, bytes.NewReader(body), "application/json", &report)
+	return report, err
+}
+
+// Import reads mock files from dir, a subdirectory of the import directory of the server.
+func (c *Client) Import(ctx context.Context, dir string, options model.ImportOptions) ([]model.ImportResult, error) {
+	query := importQuery(options)
+	setNotEmpty(query, "dir", dir)
+	var results []model.ImportResult
+	err := c.do(ctx, http.MethodGet, "/config/import", query, nil, "", &results)
+	return results, err
+}
+
+// Export writes the selected mocks to dir, a subdirectory of the export directory of the server, and returns the written files.
+func (c *Client) Export(ctx context.Context, dir string, format string, selector model.Selector) ([]string, error) {
+	query := selectorQuery(selector)
+	setNotEmpty(query, "dir", dir)
+	setNotEmpty(query, "format", format)
+	var files []string
+	err := c.do(ctx, http.MethodGet, "/config/export", query, nil, "", &files)
+	return files, err
+}
+
+// Upload imports mock files and archives.
+func (c *Client) Upload(ctx context.Context, files []model.MockFile, options model.ImportOptions) ([]model.ImportResult, error) {
+	return c.upload(ctx, "/config/upload", files, importQuery(options))
+}
+
+// Download returns the selected mocks as archive ("zip" or "tar.gz") of files in format ("json" or "yaml").
+func (c *Client) Download(ctx context.Context, archive string, format string, selector model.Selector) ([]byte, error) {
+	query := selectorQuery(selector)
+	setNotEmpty(query, "archive", archive)
+	setNotEmpty(query, "format", format)
+	var buf bytes.Buffer
+	err := c.do(ctx, http.MethodGet, "/config/download", query, nil, "", &buf)
+	return buf.Bytes(), err
+}
+
+func (c *Client) ImportOpenAPI(ctx context.Context, contents []byte, openapiOptions openapi.Options, options model.ImportOptions) ([]model.ImportResult, error) {
+	query := importQuery(options)
+	setNotEmpty(query, "paths", openapiOptions.Paths)
+	setNotEmpty(query, "filename", openapiOptions.File)
+	var results []model.ImportResult
+	err := c.do(ctx, http.MethodPost, "/config/openapi", query, bytes.NewReader(contents), "application/yaml", &results)
+	return results, err
+}
+
+func (c *Client) ImportPostman(ctx context.Context, file model.MockFile, options model.ImportOptions) ([]model.ImportResult, error) {
+	return c.importFile(ctx, "/config/postman", file, importQuery(options))
+}
+
+func (c *Client) ImportPosting(ctx context.Context, files []model.MockFile, options model.ImportOptions) ([]model.ImportResult, error) {
+	return c.upload(ctx, "/config/posting", files, importQuery(options))
+}
+
+func (c *Client) ImportHAR(ctx context.Context, file model.MockFile, harOptions har.Options, options model.ImportOptions) ([]model.ImportResult, error) {
+	query := importQuery(options)
+	query.Set("body", strconv.FormatBool(harOptions.Body))
+	query.Set("query", strconv.FormatBool(harOptions.Query))
+	setNotEmpty(query, "headers", strings.Join(harOptions.Headers, ","))
+	return c.importFile(ctx, "/config/har", file, query)
+}
+
+func (c *Client) ImportWireMock(ctx context.Context, files []model.MockFile, options model.ImportOptions) ([]model.ImportResult, error) {
+	return c.upload(ctx, "/config/wiremock", files, importQuery(options))
+}
+
+func (c *Client) ExportWireMock(ctx context.Context, selector model.Selector) (wiremock.Mappings, error) {
+	var mappings wiremock.Mappings
+	err := c.do(ctx, http.MethodGet, "/config/wiremock", selectorQuery(selector), nil, "", &mappings)
+	return mappings, err
+}
+
+func (c *Client) ImportPact(ctx context.Context, file model.MockFile, options model.ImportOptions) ([]model.ImportResult, error) {
+	return c.importFile(ctx, "/config/pact", file, importQuery(options))
+}
+
+// VerifyPact reports the interactions of the pact exercised by requests in the journal.
+func (c *Client) VerifyPact(ctx context.Context, contents []byte) (pact.Report, error) {
+	var report pact.Report
+	err := c.do(ctx, http.MethodPost, "/config/pact/verify", nil, bytes.NewReader(contents), "application/json", &report)
+	return report, err
+}
+
+func (c *Client) Sessions(ctx context.Context) ([]model.Session, error) {
+	var sessions []model.Session
+	err := c.do(ctx, http.MethodGet, "/config/session", nil, nil, "", &sessions)
+	return sessions, err
+}
+
+func (c *Client) CreateSession(ctx context.Context) (model.Session, error) {
+	var session model.Session
+	err := c.do(ctx, http.MethodPost, "/config/session", nil, nil, "", &session)
+	return session, err
+}
+
+// DeleteSession removes the session id with its mocks and journal entries.
+func (c *Client) DeleteSession(ctx context.Context, id string) (model.SessionCleanup, error) {
+	var cleanup model.SessionCleanup
+	err := c.do(ctx, http.MethodDelete, "/config/session", url.Values{"id": {id}}, nil, "", &cleanup)
+	return cleanup, err
+}
+
+// Journal lists the requests received in the namespace, or in the session of the client.
+func (c *Client) Journal(ctx context.Context) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	err := c.do(ctx, http.MethodGet, "/config/journal", nil, nil, "", &entries)
+	return entries, err
+}
+
+func (c *Client) JournalHAR(ctx context.Context) (har.HAR, error) {
+	var archive har.HAR
+	err := c.do(ctx, http.MethodGet, "/config/journal", url.Values{"format": {"har"}}, nil, "", &archive)
+	return archive, err
+}
+
+func (c *Client) ClearJournal(ctx context.Context) (int, error) {
+	return c.count(ctx, http.MethodDelete, "/config/journal", nil)
+}
+
+// Requests lists the journal entries of requests answered by mock.
+func (c *Client) Requests(ctx context.Context, mock model.Mock) ([]model.JournalEntry, error) {
+	entries, err := c.Journal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var requests []model.JournalEntry
+	for _, entry := range entries {
+		if entry.MockID == mock.ID {
+			requests = append(requests, entry)
+		}
+	}
+	return requests, nil
+}
+
+func (c *Client) count(ctx context.Context, method string, path string, query url.Values) (int, error) {
+	var count struct {
+		Count int `json:"count"`
+	}
+	err := c.do(ctx, method, path, query, nil, "", &count)
+	return count.Count, err
+}
+
+func (c *Client) importFile(ctx context.Context, path string, file model.MockFile, query url.Values) ([]model.ImportResult, error) {
+	setNotEmpty(query, "filename", file.Name)
+	var results []model.ImportResult
+	err := c.do(ctx, http.MethodPost, path, query, bytes.NewReader(file.Contents), "application/json", &results)
+	return results, err
+}
+
+// upload sends files as 'file' fields of a multipart form.
+func (c *Client) upload(ctx context.Context, path string, files []model.MockFile, query url.Values) ([]model.ImportResult, error) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	for _, file := range files {
+		part, err := form.CreateFormFile("file", file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = part.Write(file.Contents); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+	var results []model.ImportResult
+	err := c.do(ctx, http.MethodPost, path, query, &buf, form.FormDataContentType(), &results)
+	return results, err
+}
+
+// do sends a request and decodes the JSON response into out, or copies it if out is a bytes.Buffer.
+// Responses with status 400 and above are returned as *Error.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body io.Reader, contentType string, out any) error {
+	target := c.BaseURL + path
+	if len(query) != 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	if len(contentType) != 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(c.Namespace) != 0 {
+		req.Header.Set(c.NamespaceHeader, c.Namespace)
+	}
+	if len(c.SessionID) != 0 {
+		req.Header.Set(model.SessionHeader, c.SessionID)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errorOf(resp.StatusCode, data)
+	}
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err = out.Write(data)
+		return err
+	default:
+		return json.Unmarshal(data, out)
+	}
+}
+
+// errorOf reads the validation messages of rejected mocks, sent as JSON list.
+func errorOf(status int, body []byte) *Error {
+	e := &Error{StatusCode: status, Message: strings.TrimSpace(string(body))}
+	if status == http.StatusBadRequest && json.Unmarshal(body, &e.ValidationErrors) == nil {
+		e.Message = ErrInvalidMock.Error()
+	}
+	return e
+}
+
+func selectorQuery(selector model.Selector) url.Values {
+	query := url.Values{}
+	if selector.ID != 0 {
+		query.Set("id", strconv.FormatInt(selector.ID, 10))
+	}
+	setNotEmpty(query, "name", selector.Name)
+	for _, tag := range selector.Tags {
+		query.Add("tag", tag)
+	}
+	return query
+}
+
+func importQuery(options model.ImportOptions) url.Values {
+	query := url.Values{}
+	setNotEmpty(query, "folders", options.Folders)
+	setNotEmpty(query, "mode", options.Mode)
+	if options.DryRun {
+		query.Set("dryRun", "true")
+	}
+	return query
+}
+
+func setNotEmpty(query url.Values, key string, value string) {
+	if len(value) != 0 {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,200 @@
+package client_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rromanowicz/mockery/client"
+	"github.com/rromanowicz/mockery/har"
+	"github.com/rromanowicz/mockery/mockerytest"
+	"github.com/rromanowicz/mockery/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Mocks(t *testing.T) {
+	srv := mockerytest.NewServer(t)
+	c := srv.Client()
+	ctx := t.Context()
+
+	assert.NoError(t, c.Health(ctx))
+	settings, err := c.Settings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, model.InMemory, settings.DBType)
+	mocks, err := c.Stub(ctx,
+		client.Mock().Get("/persons").WithQuery("id", "1").Named("person").Tagged("persons").Returns(200, `{"name": "John"}`),
+		client.Mock().Post("/persons").WithBody("$.name", "Jane").Tagged("persons").Returns(201, map[string]any{"id": 2}),
+		client.Mock().Get("/orders").Tagged("orders").Returns(200, nil),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, mocks, 3)
+	assert.NotZero(t, mocks[0].ID)
+
+	listed, err := c.List(ctx, model.Selector{Tags: []string{"persons"}})
+	assert.NoError(t, err)
+	assert.Equal(t, mocks[:2], listed)
+
+	count, err := c.Disable(ctx, model.Selector{Name: "person"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = c.Enable(ctx, model.Selector{Name: "person"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	snippets, err := c.Snippets(ctx, model.Selector{ID: mocks[0].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, srv.URL+"/persons?id=1", snippets[0].URL)
+
+	report, err := c.Match(ctx, model.JournalRequest{Method: "POST", URL: "/persons", Body: `{"name": "Jane"}`})
+	assert.NoError(t, err)
+	assert.Equal(t, mocks[1].ID, report.Mock.ID)
+
+	resp, err := http.Get(snippets[0].URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	requests, err := c.Requests(ctx, mocks[0])
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	count, err = c.ClearJournal(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	archive, err := c.Download(ctx, "zip", "yaml", model.Selector{Tags: []string{"orders"}})
+	assert.NoError(t, err)
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	assert.Len(t, reader.File, 1)
+
+	mappings, err := c.ExportWireMock(ctx, model.Selector{Tags: []string{"orders"}})
+	assert.NoError(t, err)
+	assert.Len(t, mappings.Mappings, 1)
+
+	assert.NoError(t, c.Delete(ctx, mocks[2].ID))
+	count, err = c.DeleteAll(ctx, model.Selector{Tags: []string{"persons"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	results, err := c.Upload(ctx, []model.MockFile{{Name: "orders.yaml", Contents: archiveFile(t, archive)}}, model.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportOK, results[0].Status)
+	count, err = c.Reset(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func archiveFile(t *testing.T, archive []byte) []byte {
+	t.Helper()
+	reader, _ := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	file, err := reader.File[0].Open()
+	assert.NoError(t, err)
+	defer file.Close()
+	var buf bytes.Buffer
+	buf.ReadFrom(file)
+	return buf.Bytes()
+}
+
+func TestClient_Errors(t *testing.T) {
+	c := mockerytest.NewServer(t).Client()
+	ctx := t.Context()
+
+	_, err := c.Create(ctx, model.Mock{Method: "GET", ResponseStatus: 200})
+	assert.ErrorIs(t, err, client.ErrInvalidMock)
+	var apiErr *client.Error
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, []string{model.InvalidPath}, apiErr.ValidationErrors)
+
+	_, err = c.DeleteAll(ctx, model.Selector{})
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.NotErrorIs(t, err, client.ErrInvalidMock)
+
+	_, err = c.DeleteSession(ctx, "unknown")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	_, err = c.Stub(ctx, client.Mock().Get("/persons").Returns(200, `[1, 2]`))
+	assert.Error(t, err)
+}
+
+func TestClient_Sessions(t *testing.T) {
+	c := mockerytest.NewServer(t).Client().InNamespace("billing")
+	ctx := t.Context()
+
+	session, err := c.CreateSession(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", session.Namespace)
+	sessions, err := c.Sessions(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	scoped := c.InSession(session.ID)
+	mocks, err := scoped.Stub(ctx, client.Mock().Get("/invoices").Returns(200, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID, mocks[0].SessionID)
+	assert.Equal(t, "billing", mocks[0].Namespace)
+
+	req, _ := http.NewRequest("GET", c.BaseURL+"/invoices", nil)
+	req.Header.Set(model.DefaultNamespaceHeader, "billing")
+	req.Header.Set(model.SessionHeader, session.ID)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	entries, err := scoped.Journal(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	archive, err := scoped.JournalHAR(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, archive.Log.Entries, 1)
+
+	cleanup, err := c.DeleteSession(ctx, session.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SessionCleanup{Mocks: 1, JournalEntries: 1}, cleanup)
+}
+
+func TestClient_Imports(t *testing.T) {
+	c := mockerytest.NewServer(t).Client()
+	ctx := t.Context()
+
+	recording := []byte(`{"log": {"entries": [{"request": {"method": "GET", "url": "http://api.test/persons?id=1"},
+		"response": {"status": 200, "content": {"mimeType": "application/json", "text": "{\"name\": \"John\"}"}}}]}}`)
+	results, err := c.ImportHAR(ctx, model.MockFile{Name: "persons.har", Contents: recording}, har.DefaultOptions(), model.ImportOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportOK, results[0].Status)
+	mocks, _ := c.List(ctx, model.Selector{})
+	assert.Empty(t, mocks)
+
+	contract := []byte(`{"consumer": {"name": "web"}, "provider": {"name": "persons"}, "interactions": [
+		{"description": "get person", "request": {"method": "GET", "path": "/persons/1"}, "response": {"status": 200}}]}`)
+	results, err = c.ImportPact(ctx, model.MockFile{Name: "web-persons.json", Contents: contract}, model.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportOK, results[0].Status)
+	report, err := c.VerifyPact(ctx, contract)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Total)
+	assert.False(t, report.Verified)
+
+	_, err = c.ImportPact(ctx, model.MockFile{Name: "broken.json", Contents: []byte(`{}`)}, model.ImportOptions{})
+	var apiErr *client.Error
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestMockBuilder(t *testing.T) {
+	mock, err := client.Mock().Put("/persons/1").WithHeader("X-Id", "1").Tagged("persons").Returns(200, `{"id": 1}`).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", mock.Method)
+	assert.Equal(t, "/persons/1", mock.Path)
+	assert.Equal(t, "X-Id", mock.RequestHeaderMatchers[0].Key)
+	assert.Equal(t, float64(1), mock.ResponseBody["id"])
+
+	mock, _ = client.Mock().Get("/persons").WithRegexPath("^/persons/[0-9]+$").Build()
+	assert.Empty(t, mock.Path)
+	assert.Equal(t, "^/persons/[0-9]+$", mock.RegexPath)
+
+	_, err = client.Mock().Get("/persons").Returns(200, `[1, 2]`).Build()
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+)
+
+// MockBuilder describes a mock with chained calls, e.g.
+// Mock().Get("/persons").WithQuery("id", "1").Returns(200, body).
+type MockBuilder struct {
+	mock model.Mock
+	err  error
+}
+
+func Mock() *MockBuilder {
+	return &MockBuilder{mock: model.Mock{ResponseStatus: http.StatusOK}}
+}
+
+func (b *MockBuilder) Get(path string) *MockBuilder    { return b.Method(http.MethodGet, path) }
+func (b *MockBuilder) Post(path string) *MockBuilder   { return b.Method(http.MethodPost, path) }
+func (b *MockBuilder) Put(path string) *MockBuilder    { return b.Method(http.MethodPut, path) }
+func (b *MockBuilder) Patch(path string) *MockBuilder  { return b.Method(http.MethodPatch, path) }
+func (b *MockBuilder) Delete(path string) *MockBuilder { return b.Method(http.MethodDelete, path) }
+
+// Method matches requests with method on the exact path.
+func (b *MockBuilder) Method(method string, path string) *MockBuilder {
+	b.mock.Method, b.mock.Path, b.mock.RegexPath = method, path, ""
+	return b
+}
+
+// WithRegexPath matches the path with pattern instead of the exact path.
+func (b *MockBuilder) WithRegexPath(pattern string) *MockBuilder {
+	b.mock.Path, b.mock.RegexPath = "", pattern
+	return b
+}
+
+func (b *MockBuilder) Named(name string) *MockBuilder {
+	b.mock.Name = name
+	return b
+}
+
+func (b *MockBuilder) Tagged(tags ...string) *MockBuilder {
+	b.mock.Tags = append(b.mock.Tags, tags...)
+	return b
+}
+
+func (b *MockBuilder) InNamespace(namespace string) *MockBuilder {
+	b.mock.Namespace = namespace
+	return b
+}
+
+func (b *MockBuilder) WithQuery(key string, value any) *MockBuilder {
+	b.mock.RequestQueryMatchers = append(b.mock.RequestQueryMatchers, model.Matcher{Key: key, Value: value})
+	return b
+}
+
+func (b *MockBuilder) WithHeader(key string, value any) *MockBuilder {
+	b.mock.RequestHeaderMatchers = append(b.mock.RequestHeaderMatchers, model.Matcher{Key: key, Value: value})
+	return b
+}
+
+// WithBody matches the value selected by the JsonPath key, e.g. WithBody("$.name", "John").
+func (b *MockBuilder) WithBody(key string, value any) *MockBuilder {
+	b.mock.RequestBodyMatchers = append(b.mock.RequestBodyMatchers, model.Matcher{Key: key, Value: value})
+	return b
+}
+
+// WithClientCert matches a field of the client certificate: model.CertCommonName, model.CertSAN or model.CertIssuer,
+// e.g. WithClientCert(model.CertCommonName, "billing").
+func (b *MockBuilder) WithClientCert(key string, value string) *MockBuilder {
+	b.mock.RequestCertMatchers = append(b.mock.RequestCertMatchers, model.Matcher{Key: key, Value: value})
+	return b
+}
+
+// Returns sets the response. The body is a JSON object given as model.JSONB, map, struct, string or bytes, or nil.
+func (b *MockBuilder) Returns(status int, body any) *MockBuilder {
+	b.mock.ResponseStatus = status
+	switch body := body.(type) {
+	case nil:
+		b.mock.ResponseBody = nil
+	case model.JSONB:
+		b.mock.ResponseBody = body
+	case string:
+		b.mock.ResponseBody, b.err = util.ResponseBody([]byte(body))
+	case []byte:
+		b.mock.ResponseBody, b.err = util.ResponseBody(body)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			b.err = err
+			return b
+		}
+		b.mock.ResponseBody, b.err = util.ResponseBody(data)
+	}
+	return b
+}
+
+// Build returns the mock, or the error of an unsupported response body.
+func (b *MockBuilder) Build() (model.Mock, error) {
+	return b.mock, b.err
+}
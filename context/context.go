@@ -6,6 +6,7 @@ import (
 
 	"github.com/rromanowicz/mockery/db"
 	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/openapi"
 	"github.com/rromanowicz/mockery/service"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -18,11 +19,78 @@ var (
 )
 
 type Context struct {
+	Config      model.Config
 	Repository  db.MockRepoInt
 	MockService service.MockInt
+	Journal     service.JournalInt
+	Sessions    service.SessionInt
+	Watcher     *service.ImportWatcher
+	Contract    *openapi.Validator
 }
 
 func InitContext(config *model.Config) (Context, error) {
+	log.Printf("Starting server [Port: %v, DB: %s]", config.Port, config.DBType)
+
+	var contract, mockContract *openapi.Validator
+	if len(config.Contract.Document) != 0 {
+		validator, err := openapi.LoadValidator(config.Contract.Document)
+		if err != nil {
+			return Context{}, err
+		}
+		if config.Contract.Responses {
+			mockContract = validator
+		}
+		if config.Contract.Requests {
+			contract = validator
+		}
+	}
+
+	mockService, err := initMockService(config, mockContract)
+	if err != nil {
+		return Context{}, err
+	}
+	if config.AutoImport {
+		imported, err := mockService.Import("", model.ImportOptions{})
+		if err != nil {
+			log.Println(err)
+		} else {
+			log.Printf("Imported mocks: %v", imported)
+		}
+	}
+
+	var watcher *service.ImportWatcher
+	if config.WatchImport {
+		watcher = service.InitImportWatcher(mockService, config.WatchInterval, config.AutoImport)
+		watcher.Start()
+	}
+
+	journal := service.InitJournalService(config.JournalSize)
+
+	return Context{
+		Config:      *config,
+		Repository:  mockService.Repository,
+		MockService: mockService,
+		Journal:     journal,
+		Sessions:    service.InitSessionService(mockService, journal),
+		Watcher:     watcher,
+		Contract:    contract,
+	}, nil
+}
+
+// InitMockService connects to the configured database, without starting anything else.
+// Mocks are checked against the contract document like on the server, if configured.
+func InitMockService(config *model.Config) (service.MockService, error) {
+	var mockContract *openapi.Validator
+	if len(config.Contract.Document) != 0 && config.Contract.Responses {
+		var err error
+		if mockContract, err = openapi.LoadValidator(config.Contract.Document); err != nil {
+			return service.MockService{}, err
+		}
+	}
+	return initMockService(config, mockContract)
+}
+
+func initMockService(config *model.Config, mockContract *openapi.Validator) (service.MockService, error) {
 	var repo db.MockRepoInt
 	var dbParams model.DBParams
 	var dbDriverFn func(str string) gorm.Dialector
@@ -37,25 +105,20 @@ func InitContext(config *model.Config) (Context, error) {
 		dbDriverFn = postgres.Open
 		dbParams = config.DBConfig.Postgres
 	}
-
-	log.Printf("Starting server [Port: %v, DB: %s]", config.Port, config.DBType)
-
-	service := service.InitMockService(repo, dbDriverFn, dbParams)
-	if config.AutoImport {
-		imported, err := service.Import()
-		if err != nil {
-			log.Println(err)
-		} else {
-			log.Printf("Imported mocks: %v", imported)
-		}
+	mockService, err := service.InitMockService(repo, dbDriverFn, dbParams, config)
+	if err == nil && mockContract != nil {
+		mockService.Contract = mockContract.ValidateMock
 	}
-
-	return Context{
-		Repository:  repo,
-		MockService: service,
-	}, nil
+	return mockService, err
 }
 
-func (ctx Context) Close() {
-	ctx.Repository.CloseDB()
+// Close stops the import watcher and closes the database.
+func (ctx Context) Close() error {
+	if ctx.Watcher != nil {
+		ctx.Watcher.Stop()
+	}
+	if ctx.Repository == nil {
+		return nil
+	}
+	return ctx.Repository.CloseDB()
 }
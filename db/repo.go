@@ -8,23 +8,28 @@ import (
 )
 
 type MockRepoInt interface {
-	InitDB(driverFn func(str string) gorm.Dialector, dbParams model.DBParams) MockRepoInt
-	CloseDB()
-	FindByMethodAndPath(method string, path string) ([]model.Mock, error)
-	FindByID(id int64) (model.Mock, error)
-	FindByIDs(ids []int64) ([]model.Mock, error)
-	DeleteByID(id int64) error
+	InitDB(driverFn func(str string) gorm.Dialector, dbParams model.DBParams) (MockRepoInt, error)
+	CloseDB() error
+	FindByMethodAndPath(namespace string, sessionID string, method string, path string) ([]model.Mock, error)
+	FindByID(namespace string, id int64) (model.Mock, error)
+	FindByIDs(namespace string, sessionID string, ids []int64) ([]model.Mock, error)
+	DeleteByID(namespace string, id int64) error
 	Save(mock model.Mock) (model.Mock, error)
 	GetAll() ([]model.Mock, error)
-	Import() ([]string, error)
-	Export() ([]string, error)
-	GetRegexpMatchers(method string) ([]model.RegexMatcher, error)
+	FindBySelector(selector model.Selector) ([]model.Mock, error)
+	DeleteBySelector(selector model.Selector) (int, error)
+	SetDisabled(selector model.Selector, disabled bool) (int, error)
+	Import(importDir string, options model.ImportOptions) ([]model.ImportResult, error)
+	ApplyImport(results []model.ImportResult, options model.ImportOptions) ([]model.ImportResult, error)
+	ReplaceSource(source string, results []model.ImportResult) ([]model.ImportResult, error)
+	Export(exportDir string, format string, selector model.Selector) ([]string, error)
+	GetRegexpMatchers(namespace string, sessionID string, method string) ([]model.RegexMatcher, error)
 }
 
-func ExportMocks(mocks []model.Mock) ([]string, error) {
-	return util.Export(model.ExportDir, mocks)
+func ExportMocks(exportDir string, format string, mocks []model.Mock) ([]string, error) {
+	return util.Export(exportDir, mocks, format)
 }
 
-func ImportMocks() ([]model.Mock, []string, error) {
-	return util.Import(model.ImportDir)
+func ImportMocks(importDir string, options model.ImportOptions) ([]model.ImportResult, error) {
+	return util.Import(importDir, options)
 }
@@ -1,54 +1,75 @@
 package db
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"slices"
 	"sync"
 
 	"github.com/rromanowicz/mockery/model"
 	"gorm.io/gorm"
 )
 
+const (
+	FailedToConnect = "Failed to connect database"
+	FailedToMigrate = "Failed to migrate database"
+)
+
 type MockRepoImpl struct {
 	DBConn *gorm.DB
 	lock   *sync.RWMutex
 }
 
-func (mr MockRepoImpl) InitDB(driverFn func(str string) gorm.Dialector, dbParams model.DBParams) MockRepoInt {
+func (mr MockRepoImpl) InitDB(driverFn func(str string) gorm.Dialector, dbParams model.DBParams) (MockRepoInt, error) {
 	db, err := gorm.Open(driverFn(dbParams.ConnectionString), &gorm.Config{})
 	if err != nil {
-		panic("failed to connect database")
+		return mr, fmt.Errorf("%s. %w", FailedToConnect, err)
 	}
 
-	db.AutoMigrate(&model.Mock{})
+	if err = db.AutoMigrate(&model.Mock{}); err != nil {
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			sqlDB.Close()
+		}
+		return mr, fmt.Errorf("%s. %w", FailedToMigrate, err)
+	}
 
 	mr.DBConn = db
 
-	return mr
+	return mr, nil
+}
+
+// CloseDB closes the connection pool of the database. In-memory databases are gone afterwards.
+func (mr MockRepoImpl) CloseDB() error {
+	if mr.DBConn == nil {
+		return nil
+	}
+	sqlDB, err := mr.DBConn.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
-func (mr MockRepoImpl) CloseDB() {}
-func (mr MockRepoImpl) FindByMethodAndPath(method string, path string) ([]model.Mock, error) {
-	mocks, err := gorm.G[model.Mock](mr.DBConn).Where("method=? and path is not null and path=?", method, path).Find(context.Background())
+func (mr MockRepoImpl) FindByMethodAndPath(namespace string, sessionID string, method string, path string) ([]model.Mock, error) {
+	mocks, err := gorm.G[model.Mock](mr.DBConn).Where("namespace=? and session_id in ? and method=? and path is not null and path=? and disabled=?", namespace, sessionScope(sessionID), method, path, false).Order("id").Find(context.Background())
 	return mocks, err
 }
 
-func (mr MockRepoImpl) FindByID(id int64) (model.Mock, error) {
+func (mr MockRepoImpl) FindByID(namespace string, id int64) (model.Mock, error) {
 	ctx := context.Background()
-	mock, err := gorm.G[model.Mock](mr.DBConn).Where("id = ?", id).First(ctx)
+	mock, err := gorm.G[model.Mock](mr.DBConn).Where("namespace = ? and id = ?", namespace, id).First(ctx)
 	return mock, err
 }
 
-func (mr MockRepoImpl) FindByIDs(ids []int64) ([]model.Mock, error) {
-	idString := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(ids)), ","), "[]")
-	mocks, err := gorm.G[model.Mock](mr.DBConn).Where("id in (?)", idString).Find(context.Background())
+func (mr MockRepoImpl) FindByIDs(namespace string, sessionID string, ids []int64) ([]model.Mock, error) {
+	mocks, err := gorm.G[model.Mock](mr.DBConn).Where("namespace = ? and session_id in ? and id in ?", namespace, sessionScope(sessionID), ids).Order("id").Find(context.Background())
 	return mocks, err
 }
 
-func (mr MockRepoImpl) DeleteByID(id int64) error {
-	_, err := gorm.G[model.Mock](mr.DBConn).Where("id = ?", id).Delete(context.Background())
+func (mr MockRepoImpl) DeleteByID(namespace string, id int64) error {
+	_, err := gorm.G[model.Mock](mr.DBConn).Where("namespace = ? and id = ?", namespace, id).Delete(context.Background())
 	return err
 }
 
@@ -65,29 +86,218 @@ func (mr MockRepoImpl) GetAll() ([]model.Mock, error) {
 	return mocks, err
 }
 
-func (mr MockRepoImpl) Import() ([]string, error) {
-	mocks, files, err := ImportMocks()
+func (mr MockRepoImpl) FindBySelector(selector model.Selector) ([]model.Mock, error) {
+	mocks, err := gorm.G[model.Mock](mr.DBConn).Where("namespace = ?", selector.Namespace).Where(&model.Mock{ID: selector.ID, Name: selector.Name, SessionID: selector.SessionID}).Find(context.Background())
 	if err != nil {
-		log.Println("Failed to read mocks.")
-		return []string{}, err
+		return []model.Mock{}, err
 	}
+	selected := make([]model.Mock, 0, len(mocks))
 	for i := range mocks {
-		_, err = mr.Save(mocks[i])
+		if selector.Matches(mocks[i]) {
+			selected = append(selected, mocks[i])
+		}
+	}
+	return selected, nil
+}
+
+func (mr MockRepoImpl) DeleteBySelector(selector model.Selector) (int, error) {
+	ids, err := mr.selectIDs(selector)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return gorm.G[model.Mock](mr.DBConn).Where("id in ?", ids).Delete(context.Background())
+}
+
+func (mr MockRepoImpl) SetDisabled(selector model.Selector, disabled bool) (int, error) {
+	ids, err := mr.selectIDs(selector)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return gorm.G[model.Mock](mr.DBConn).Where("id in ?", ids).Update(context.Background(), "disabled", disabled)
+}
+
+func (mr MockRepoImpl) selectIDs(selector model.Selector) ([]int64, error) {
+	mocks, err := mr.FindBySelector(selector)
+	if err != nil {
+		return []int64{}, err
+	}
+	var ids []int64
+	for i := range mocks {
+		ids = append(ids, mocks[i].ID)
+	}
+	return ids, nil
+}
+
+func (mr MockRepoImpl) Import(importDir string, options model.ImportOptions) ([]model.ImportResult, error) {
+	results, err := ImportMocks(importDir, options)
+	if err != nil {
+		log.Println("Failed to read mocks.")
+		return []model.ImportResult{}, err
+	}
+	return mr.ApplyImport(results, options)
+}
+
+// ApplyImport stores mocks of successful results according to the import mode and fills in the performed actions.
+// Mocks without a stored match always get a new id, so an import can not overwrite mocks of other namespaces.
+// In sync mode, global mocks of the affected namespaces missing in the import (or duplicating an imported one) are deleted,
+// limited to imported mocks in the synced sources (see model.ImportOptions.Synced). Nothing is deleted while any result failed,
+// so a broken file keeps the mocks it defined.
+func (mr MockRepoImpl) ApplyImport(results []model.ImportResult, options model.ImportOptions) ([]model.ImportResult, error) {
+	var stored []model.Mock
+	existing := map[string]model.Mock{}
+	if options.Mode == model.ImportUpsert || options.Mode == model.ImportSync {
+		var err error
+		stored, err = mr.GetAll()
 		if err != nil {
-			log.Println("Failed to save mock.")
-			return []string{}, err
+			log.Println("Failed to fetch mocks.")
+			return []model.ImportResult{}, err
+		}
+		slices.SortFunc(stored, func(a, b model.Mock) int { return cmp.Compare(a.ID, b.ID) })
+		for i := range stored {
+			if _, ok := existing[stored[i].NaturalKey()]; !ok {
+				existing[stored[i].NaturalKey()] = stored[i]
+			}
 		}
 	}
-	return files, nil
+
+	imported := map[string]int64{}
+	namespaces := map[string]bool{options.Namespace: true}
+	failed := false
+	for i := range results {
+		if results[i].Status != model.ImportOK {
+			failed = true
+			continue
+		}
+		mock := *results[i].Mock
+		if len(mock.Namespace) == 0 {
+			mock.Namespace = options.Namespace
+		}
+		key := mock.NaturalKey()
+		results[i].Key = key
+		namespaces[mock.Namespace] = true
+		if _, ok := imported[key]; ok && options.Mode != model.ImportCreate {
+			results[i].Status, results[i].Errors = model.ImportFailed, []string{model.DuplicateNaturalKey}
+			failed = true
+			continue
+		}
+
+		results[i].Action = model.ActionCreated
+		if current, ok := existing[key]; ok {
+			mock.ID = current.ID
+			results[i].Action = model.ActionUpdated
+			if current.Equals(mock) {
+				results[i].Action = model.ActionUnchanged
+			}
+		} else {
+			mock.ID = 0
+		}
+		if !options.DryRun && results[i].Action != model.ActionUnchanged {
+			saved, err := mr.Save(mock)
+			if err != nil {
+				log.Println("Failed to save mock.")
+				return []model.ImportResult{}, err
+			}
+			mock.ID = saved.ID
+		}
+		results[i].MockID = mock.ID
+		imported[key] = mock.ID
+	}
+
+	if options.Mode == model.ImportSync && failed {
+		log.Println("Skipping deletes of sync import - some mocks failed to import.")
+	} else if options.Mode == model.ImportSync {
+		for _, mock := range stored {
+			key := mock.NaturalKey()
+			if id, ok := imported[key]; (ok && id == mock.ID) || len(mock.SessionID) != 0 || !namespaces[mock.Namespace] {
+				continue
+			}
+			if !options.Synced(mock.Source) {
+				continue
+			}
+			if !options.DryRun {
+				if err := mr.DeleteByID(mock.Namespace, mock.ID); err != nil {
+					log.Println("Failed to delete mock.")
+					return []model.ImportResult{}, err
+				}
+			}
+			results = append(results, model.ImportResult{Status: model.ImportOK, Action: model.ActionDeleted, MockID: mock.ID, Key: key})
+		}
+	}
+	return results, nil
 }
 
-func (mr MockRepoImpl) Export() ([]string, error) {
-	mocks, err := mr.GetAll()
+// ReplaceSource makes the global mocks imported from source match the successful results.
+// Mocks are matched by their natural key, unmatched stored mocks are deleted.
+func (mr MockRepoImpl) ReplaceSource(source string, results []model.ImportResult) ([]model.ImportResult, error) {
+	stored, err := gorm.G[model.Mock](mr.DBConn).Where("source = ? and session_id = ?", source, "").Order("id").Find(context.Background())
+	if err != nil {
+		log.Println("Failed to fetch mocks.")
+		return []model.ImportResult{}, err
+	}
+	existing := map[string]model.Mock{}
+	for i := range stored {
+		if _, ok := existing[stored[i].NaturalKey()]; !ok {
+			existing[stored[i].NaturalKey()] = stored[i]
+		}
+	}
+
+	kept := map[int64]bool{}
+	imported := map[string]bool{}
+	for i := range results {
+		if results[i].Status != model.ImportOK {
+			continue
+		}
+		mock := *results[i].Mock
+		mock.Source = source
+		key := mock.NaturalKey()
+		results[i].Key = key
+		if imported[key] {
+			results[i].Status, results[i].Errors = model.ImportFailed, []string{model.DuplicateNaturalKey}
+			continue
+		}
+		imported[key] = true
+
+		mock.ID = 0
+		results[i].Action = model.ActionCreated
+		if current, ok := existing[key]; ok {
+			mock.ID = current.ID
+			kept[current.ID] = true
+			results[i].Action = model.ActionUpdated
+			if current.Equals(mock) {
+				results[i].Action = model.ActionUnchanged
+			}
+		}
+		if results[i].Action != model.ActionUnchanged {
+			saved, err := mr.Save(mock)
+			if err != nil {
+				log.Println("Failed to save mock.")
+				return []model.ImportResult{}, err
+			}
+			mock.ID = saved.ID
+		}
+		results[i].MockID = mock.ID
+	}
+
+	for _, mock := range stored {
+		if kept[mock.ID] {
+			continue
+		}
+		if err := mr.DeleteByID(mock.Namespace, mock.ID); err != nil {
+			log.Println("Failed to delete mock.")
+			return []model.ImportResult{}, err
+		}
+		results = append(results, model.ImportResult{Status: model.ImportOK, Action: model.ActionDeleted, MockID: mock.ID, Key: mock.NaturalKey()})
+	}
+	return results, nil
+}
+
+func (mr MockRepoImpl) Export(exportDir string, format string, selector model.Selector) ([]string, error) {
+	mocks, err := mr.FindBySelector(selector)
 	if err != nil {
 		log.Println("Failed to fetch mocks.")
 		return []string{}, err
 	}
-	files, err := ExportMocks(mocks)
+	files, err := ExportMocks(exportDir, format, mocks)
 	if err != nil {
 		log.Println("Failed to save mock.")
 		return []string{}, err
@@ -95,7 +305,15 @@ func (mr MockRepoImpl) Export() ([]string, error) {
 	return files, nil
 }
 
-func (mr MockRepoImpl) GetRegexpMatchers(method string) ([]model.RegexMatcher, error) {
-	mocks, err := gorm.G[model.RegexMatcher](mr.DBConn).Raw("select id, method, regex_path from mocks where method=? and regex_path is not null and regex_path != ''", method).Find(context.Background())
+func (mr MockRepoImpl) GetRegexpMatchers(namespace string, sessionID string, method string) ([]model.RegexMatcher, error) {
+	mocks, err := gorm.G[model.RegexMatcher](mr.DBConn).Raw("select id, method, regex_path from mocks where namespace=? and session_id in ? and method=? and regex_path is not null and regex_path != '' and disabled=? order by id", namespace, sessionScope(sessionID), method, false).Find(context.Background())
 	return mocks, err
 }
+
+// sessionScope lists the sessions visible to a request: global mocks and, if provided, mocks of its own session.
+func sessionScope(sessionID string) []string {
+	if len(sessionID) == 0 {
+		return []string{""}
+	}
+	return []string{"", sessionID}
+}
@@ -0,0 +1,240 @@
+// Package har
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+)
+
+const Version = "1.2"
+
+var ErrInvalidHAR = errors.New("Invalid HAR file")
+
+// HAR is an HTTP Archive as saved by browser devtools and proxies.
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         Request   `json:"request"`
+	Response        Response  `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         Timings   `json:"timings"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Options select the matchers generated for recorded requests.
+type Options struct {
+	// Body matches every value of a JSON request body.
+	Body bool
+	// Headers lists the request headers to match, all other headers are ignored.
+	Headers []string
+	Query   bool
+}
+
+func DefaultOptions() Options {
+	return Options{Body: true, Query: true}
+}
+
+// Read turns the entries of a HAR file into mocks responding with the recorded status and body.
+// Requests recorded more than once are mocked with their last response. Hosts are added as tags.
+func Read(file string, contents []byte, options Options) ([]model.ImportResult, error) {
+	var archive HAR
+	if err := json.Unmarshal(contents, &archive); err != nil {
+		return []model.ImportResult{}, fmt.Errorf("%w. %s", ErrInvalidHAR, err.Error())
+	}
+	results := []model.ImportResult{}
+	index := map[string]int{}
+	for _, entry := range archive.Log.Entries {
+		mock, err := entry.mock(options)
+		if err != nil {
+			results = append(results, failed(file, fmt.Sprintf("%s %s: %s", entry.Request.Method, entry.Request.URL, err.Error())))
+			continue
+		}
+		result := model.ImportResult{File: file, Status: model.ImportOK, Mock: &mock}
+		if valid, errs := mock.Validate(); !valid {
+			result = failed(file, errs...)
+		}
+		if i, ok := index[mock.NaturalKey()]; ok {
+			results[i] = result
+			continue
+		}
+		index[mock.NaturalKey()] = len(results)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (e Entry) mock(options Options) (model.Mock, error) {
+	requestURL, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return model.Mock{}, err
+	}
+	mock := model.Mock{
+		Method:         strings.ToUpper(e.Request.Method),
+		Path:           requestURL.EscapedPath(),
+		ResponseStatus: e.Response.Status,
+	}
+	if len(mock.Path) == 0 {
+		mock.Path = "/"
+	}
+	if !e.StartedDateTime.IsZero() {
+		mock.Description = fmt.Sprintf("Recorded %s", e.StartedDateTime.Format(time.RFC3339))
+	}
+	if len(requestURL.Hostname()) != 0 {
+		mock.Tags = model.Tags{requestURL.Hostname()}
+	}
+	if options.Query {
+		query := requestURL.Query()
+		for _, name := range slices.Sorted(maps.Keys(query)) {
+			mock.RequestQueryMatchers = append(mock.RequestQueryMatchers, model.Matcher{Key: name, Value: query.Get(name)})
+		}
+	}
+	for _, name := range options.Headers {
+		for _, header := range e.Request.Headers {
+			if strings.EqualFold(header.Name, name) {
+				mock.RequestHeaderMatchers = append(mock.RequestHeaderMatchers, model.Matcher{Key: http.CanonicalHeaderKey(name), Value: header.Value})
+				break
+			}
+		}
+	}
+	if options.Body && e.Request.PostData != nil {
+		mock.RequestBodyMatchers = util.BodyMatchers([]byte(e.Request.PostData.Text))
+	}
+
+	body := []byte(e.Response.Content.Text)
+	if e.Response.Content.Encoding == "base64" {
+		if body, err = base64.StdEncoding.DecodeString(e.Response.Content.Text); err != nil {
+			return model.Mock{}, err
+		}
+	}
+	mock.ResponseBody, err = util.ResponseBody(body)
+	return mock, err
+}
+
+func failed(file string, errs ...string) model.ImportResult {
+	return model.ImportResult{File: file, Status: model.ImportFailed, Errors: errs}
+}
+
+// FromJournal records journal entries as a HAR log. Request urls are resolved against baseURL.
+func FromJournal(entries []model.JournalEntry, baseURL string) HAR {
+	archive := HAR{Log: Log{Version: Version, Creator: Creator{Name: "mockery"}, Entries: []Entry{}}}
+	for _, journalEntry := range entries {
+		entry := Entry{
+			StartedDateTime: journalEntry.Time,
+			Request: Request{
+				Method:      journalEntry.Request.Method,
+				URL:         strings.TrimSuffix(baseURL, "/") + journalEntry.Request.URL,
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []NameValue{},
+				Headers:     nameValues(journalEntry.Request.Headers),
+				QueryString: []NameValue{},
+				HeadersSize: -1,
+				BodySize:    len(journalEntry.Request.Body),
+			},
+			Response: Response{
+				Status:      journalEntry.Response.Status,
+				StatusText:  http.StatusText(journalEntry.Response.Status),
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []NameValue{},
+				Headers:     []NameValue{{Name: "Content-Type", Value: "application/json"}},
+				Content:     Content{Size: len(journalEntry.Response.Body), MimeType: "application/json", Text: journalEntry.Response.Body},
+				HeadersSize: -1,
+				BodySize:    len(journalEntry.Response.Body),
+			},
+		}
+		if requestURL, err := url.Parse(journalEntry.Request.URL); err == nil {
+			query := requestURL.Query()
+			for _, name := range slices.Sorted(maps.Keys(query)) {
+				for _, value := range query[name] {
+					entry.Request.QueryString = append(entry.Request.QueryString, NameValue{Name: name, Value: value})
+				}
+			}
+		}
+		if len(journalEntry.Request.Body) != 0 {
+			entry.Request.PostData = &PostData{MimeType: journalEntry.Request.Headers.Get("Content-Type"), Text: journalEntry.Request.Body}
+		}
+		archive.Log.Entries = append(archive.Log.Entries, entry)
+	}
+	return archive
+}
+
+func nameValues(header http.Header) []NameValue {
+	values := []NameValue{}
+	for _, name := range slices.Sorted(maps.Keys(header)) {
+		for _, value := range header[name] {
+			values = append(values, NameValue{Name: name, Value: value})
+		}
+	}
+	return values
+}
@@ -0,0 +1,112 @@
+package har
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const archive = `{
+  "log": {
+    "version": "1.2",
+    "creator": { "name": "devtools", "version": "1.0" },
+    "entries": [
+      {
+        "startedDateTime": "2025-01-01T12:00:00Z",
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/orders?lang=en",
+          "headers": [{ "name": "x-tenant", "value": "acme" }, { "name": "User-Agent", "value": "browser" }],
+          "postData": { "mimeType": "application/json", "text": "{\"item\": \"book\", \"count\": 2}" }
+        },
+        "response": { "status": 201, "content": { "mimeType": "application/json", "text": "{\"id\": 1}" } }
+      },
+      {
+        "startedDateTime": "2025-01-01T12:00:01Z",
+        "request": { "method": "GET", "url": "https://api.example.com/orders/1" },
+        "response": { "status": 200, "content": { "mimeType": "application/json", "text": "eyJpZCI6IDEsICJzdGF0dXMiOiAib3BlbiJ9", "encoding": "base64" } }
+      },
+      {
+        "startedDateTime": "2025-01-01T12:00:02Z",
+        "request": { "method": "GET", "url": "https://api.example.com/orders/1" },
+        "response": { "status": 200, "content": { "mimeType": "application/json", "text": "{\"id\": 1, \"status\": \"paid\"}" } }
+      },
+      {
+        "startedDateTime": "2025-01-01T12:00:03Z",
+        "request": { "method": "GET", "url": "https://api.example.com/" },
+        "response": { "status": 200, "content": { "mimeType": "text/html", "text": "<html></html>" } }
+      }
+    ]
+  }
+}`
+
+func TestRead(t *testing.T) {
+	results, err := Read("traffic.har", []byte(archive), Options{Body: true, Query: true, Headers: []string{"X-Tenant"}})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	create := results[0].Mock
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "/orders", create.Path)
+	assert.Equal(t, model.Tags{"api.example.com"}, create.Tags)
+	assert.Equal(t, "Recorded 2025-01-01T12:00:00Z", create.Description)
+	assert.Equal(t, model.Matchers{{Key: "lang", Value: "en"}}, create.RequestQueryMatchers)
+	assert.Equal(t, model.Matchers{{Key: "X-Tenant", Value: "acme"}}, create.RequestHeaderMatchers)
+	assert.ElementsMatch(t, model.Matchers{{Key: "$.item", Value: "book"}, {Key: "$.count", Value: "2"}}, create.RequestBodyMatchers)
+	assert.Equal(t, 201, create.ResponseStatus)
+	assert.Equal(t, model.JSONB{"id": float64(1)}, create.ResponseBody)
+
+	assert.Equal(t, model.ImportOK, results[1].Status, results[1].Errors)
+	assert.Equal(t, model.JSONB{"id": float64(1), "status": "paid"}, results[1].Mock.ResponseBody)
+
+	assert.Equal(t, model.ImportFailed, results[2].Status)
+	assert.Equal(t, []string{"GET https://api.example.com/: " + model.UnsupportedResponseBody}, results[2].Errors)
+}
+
+func TestRead_Options(t *testing.T) {
+	results, err := Read("traffic.har", []byte(archive), Options{})
+
+	assert.NoError(t, err)
+	create := results[0].Mock
+	assert.Empty(t, create.RequestQueryMatchers)
+	assert.Empty(t, create.RequestHeaderMatchers)
+	assert.Empty(t, create.RequestBodyMatchers)
+
+	_, err = Read("traffic.har", []byte(`[]`), Options{})
+	assert.ErrorIs(t, err, ErrInvalidHAR)
+}
+
+func TestFromJournal(t *testing.T) {
+	entries := []model.JournalEntry{{
+		Time: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Request: model.JournalRequest{
+			Method:  "POST",
+			URL:     "/orders?lang=en",
+			Headers: http.Header{"Content-Type": {"application/json"}},
+			Body:    `{"item":"book"}`,
+		},
+		Response: model.JournalResponse{Status: 201, Body: `{"id":1}`},
+	}}
+
+	archive := FromJournal(entries, "http://localhost:8080/")
+
+	assert.Equal(t, Version, archive.Log.Version)
+	assert.Len(t, archive.Log.Entries, 1)
+	entry := archive.Log.Entries[0]
+	assert.Equal(t, "http://localhost:8080/orders?lang=en", entry.Request.URL)
+	assert.Equal(t, []NameValue{{Name: "lang", Value: "en"}}, entry.Request.QueryString)
+	assert.Equal(t, &PostData{MimeType: "application/json", Text: `{"item":"book"}`}, entry.Request.PostData)
+	assert.Equal(t, "Created", entry.Response.StatusText)
+	assert.Equal(t, `{"id":1}`, entry.Response.Content.Text)
+
+	mock, err := entry.mock(DefaultOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, "/orders", mock.Path)
+	assert.Equal(t, model.JSONB{"id": float64(1)}, mock.ResponseBody)
+}
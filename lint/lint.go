@@ -0,0 +1,124 @@
+// Package lint finds mocks that never answer a request, or answer it non-deterministically.
+package lint
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const (
+	RuleDuplicate        = "duplicate"
+	RuleShadowed         = "shadowed"
+	RuleRegexOverlap     = "regex-overlap"
+	RuleUnanchoredRegex  = "unanchored-regex"
+	RuleBodyMatcherOnGet = "body-matcher-on-get"
+	DuplicateMock        = "Duplicate of an earlier mock with the same method, path and matchers. Only the earlier mock answers."
+	ShadowedMock         = "Shadowed by an earlier mock with the same method and path and a subset of the matchers. This mock never answers."
+	RegexOverlapsLiteral = "Regex path matches the literal path of another mock, which takes precedence for it"
+	UnanchoredRegex      = "Regex path is not anchored with '^...$' and matches any path containing it"
+	BodyMatcherOnGet     = "Body matchers on a request method without body"
+)
+
+// Issue is a problem found in a mock. RelatedID is the mock causing it, if any.
+type Issue struct {
+	Rule      string `json:"rule"`
+	MockID    int64  `json:"mockId"`
+	Namespace string `json:"namespace,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Message   string `json:"message"`
+	RelatedID int64  `json:"relatedId,omitempty"`
+}
+
+// Mocks checks enabled mocks against mocks of the same namespace and session. Mocks are matched in model.MatchOrder,
+// so of two mocks accepting the same request the earlier one answers.
+func Mocks(mocks []model.Mock) []Issue {
+	enabled := slices.DeleteFunc(slices.Clone(mocks), func(mock model.Mock) bool { return mock.Disabled })
+	slices.SortStableFunc(enabled, model.MatchOrder)
+
+	// Regex paths are compiled once, not for every pair of mocks.
+	patterns := make([]*regexp.Regexp, len(enabled))
+	for i := range enabled {
+		if len(enabled[i].RegexPath) != 0 {
+			patterns[i] = model.RegexPath(enabled[i].RegexPath).Compile()
+		}
+	}
+
+	issues := []Issue{}
+	for i, mock := range enabled {
+		if len(mock.RegexPath) != 0 && (!strings.HasPrefix(mock.RegexPath, "^") || !strings.HasSuffix(mock.RegexPath, "$")) {
+			issues = append(issues, issueOf(RuleUnanchoredRegex, mock, UnanchoredRegex, 0))
+		}
+		if (mock.Method == http.MethodGet || mock.Method == http.MethodHead) && len(mock.RequestBodyMatchers) != 0 {
+			issues = append(issues, issueOf(RuleBodyMatcherOnGet, mock, BodyMatcherOnGet, 0))
+		}
+		overlapping := map[string]bool{}
+		for _, other := range enabled {
+			if patterns[i] == nil || len(other.Path) == 0 || overlapping[other.Path] || mock.Namespace != other.Namespace ||
+				mock.SessionID != other.SessionID || mock.Method != other.Method || !patterns[i].MatchString(other.Path) {
+				continue
+			}
+			overlapping[other.Path] = true
+			issues = append(issues, issueOf(RuleRegexOverlap, mock, fmt.Sprintf("%s [%s]", RegexOverlapsLiteral, other.Path), other.ID))
+		}
+		for _, earlier := range enabled[:i] {
+			if !sameRoute(earlier, mock) || !covers(earlier, mock) {
+				continue
+			}
+			if covers(mock, earlier) {
+				issues = append(issues, issueOf(RuleDuplicate, mock, DuplicateMock, earlier.ID))
+			} else {
+				issues = append(issues, issueOf(RuleShadowed, mock, ShadowedMock, earlier.ID))
+			}
+			break
+		}
+	}
+	return issues
+}
+
+func issueOf(rule string, mock model.Mock, message string, related int64) Issue {
+	return Issue{
+		Rule:      rule,
+		MockID:    mock.ID,
+		Namespace: mock.Namespace,
+		SessionID: mock.SessionID,
+		Name:      mock.Name,
+		Method:    mock.Method,
+		Path:      mock.Path + mock.RegexPath,
+		Message:   message,
+		RelatedID: related,
+	}
+}
+
+func sameRoute(a model.Mock, b model.Mock) bool {
+	return a.Namespace == b.Namespace && a.SessionID == b.SessionID && a.Method == b.Method && a.Path == b.Path && a.RegexPath == b.RegexPath
+}
+
+// covers reports whether every matcher of a is also a matcher of b, so a accepts every request b accepts.
+func covers(a model.Mock, b model.Mock) bool {
+	same := func(key string) string { return key }
+	return subset(a.RequestQueryMatchers, b.RequestQueryMatchers, same) &&
+		subset(a.RequestHeaderMatchers, b.RequestHeaderMatchers, http.CanonicalHeaderKey) &&
+		subset(a.RequestBodyMatchers, b.RequestBodyMatchers, same) &&
+		subset(a.RequestCertMatchers, b.RequestCertMatchers, strings.ToLower)
+}
+
+// subset reports whether b contains every matcher of a, comparing keys normalized by normalize.
+func subset(a model.Matchers, b model.Matchers, normalize func(string) string) bool {
+	key := func(matcher model.Matcher) string {
+		return normalize(matcher.Key) + "=" + fmt.Sprint(matcher.Value)
+	}
+	for _, matcher := range a {
+		if !slices.ContainsFunc(b, func(other model.Matcher) bool { return key(other) == key(matcher) }) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(issues []Issue) map[int64][]string {
+	found := map[int64][]string{}
+	for _, issue := range issues {
+		found[issue.MockID] = append(found[issue.MockID], issue.Rule)
+	}
+	return found
+}
+
+func TestMocks(t *testing.T) {
+	query := model.Matchers{{Key: "id", Value: "1"}}
+	tests := []struct {
+		name     string
+		mocks    []model.Mock
+		expected map[int64][]string
+	}{
+		{
+			"no issues",
+			[]model.Mock{
+				{ID: 1, Method: "GET", Path: "/users", RequestQueryMatchers: query},
+				{ID: 2, Method: "GET", Path: "/users"},
+				{ID: 3, Method: "POST", Path: "/users", RequestBodyMatchers: model.Matchers{{Key: "$.name", Value: "John"}}},
+				{ID: 4, Method: "GET", RegexPath: "^/users/[0-9]+$"},
+			},
+			map[int64][]string{},
+		},
+		{
+			"duplicate",
+			[]model.Mock{
+				{ID: 2, Method: "GET", Path: "/users", RequestHeaderMatchers: model.Matchers{{Key: "x-id", Value: "1"}}},
+				{ID: 1, Method: "GET", Path: "/users", RequestHeaderMatchers: model.Matchers{{Key: "X-Id", Value: "1"}}},
+			},
+			map[int64][]string{2: {RuleDuplicate}},
+		},
+		{
+			"shadowed by fewer matchers",
+			[]model.Mock{
+				{ID: 1, Method: "GET", Path: "/users"},
+				{ID: 2, Method: "GET", Path: "/users", RequestQueryMatchers: query},
+			},
+			map[int64][]string{2: {RuleShadowed}},
+		},
+		{
+			"other scopes and disabled mocks",
+			[]model.Mock{
+				{ID: 1, Method: "GET", Path: "/users"},
+				{ID: 2, Method: "GET", Path: "/users", Namespace: "billing"},
+				{ID: 3, Method: "GET", Path: "/users", SessionID: "s1"},
+				{ID: 4, Method: "GET", Path: "/users", Disabled: true},
+				{ID: 5, Method: "PUT", Path: "/users"},
+			},
+			map[int64][]string{},
+		},
+		{
+			"regex overlapping literal path",
+			[]model.Mock{
+				{ID: 1, Method: "GET", RegexPath: "^/users/.+$"},
+				{ID: 2, Method: "GET", Path: "/users/me"},
+				{ID: 3, Method: "POST", Path: "/users/me"},
+				{ID: 4, Method: "GET", Path: "/users/me", RequestQueryMatchers: model.Matchers{{Key: "id", Value: "1"}}},
+			},
+			map[int64][]string{1: {RuleRegexOverlap}, 4: {RuleShadowed}},
+		},
+		{
+			"different client certificates",
+			[]model.Mock{
+				{ID: 1, Method: "GET", Path: "/users", RequestCertMatchers: model.Matchers{{Key: "cn", Value: "billing"}}},
+				{ID: 2, Method: "GET", Path: "/users", RequestCertMatchers: model.Matchers{{Key: "CN", Value: "orders"}}},
+				{ID: 3, Method: "GET", Path: "/users", RequestCertMatchers: model.Matchers{{Key: "CN", Value: "billing"}}},
+			},
+			map[int64][]string{3: {RuleDuplicate}},
+		},
+		{
+			"fallback without client certificate",
+			[]model.Mock{
+				{ID: 1, Method: "GET", Path: "/users"},
+				{ID: 2, Method: "GET", Path: "/users", RequestCertMatchers: model.Matchers{{Key: "cn", Value: "billing"}}},
+			},
+			map[int64][]string{},
+		},
+		{
+			"unanchored regex",
+			[]model.Mock{
+				{ID: 1, Method: "GET", RegexPath: "/users/[0-9]+"},
+				{ID: 2, Method: "GET", RegexPath: "^/orders"},
+			},
+			map[int64][]string{1: {RuleUnanchoredRegex}, 2: {RuleUnanchoredRegex}},
+		},
+		{
+			"body matchers on GET",
+			[]model.Mock{{ID: 1, Method: "GET", Path: "/users", RequestBodyMatchers: model.Matchers{{Key: "$.name", Value: "John"}}}},
+			map[int64][]string{1: {RuleBodyMatcherOnGet}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rules(Mocks(tt.mocks)))
+		})
+	}
+}
+
+func TestMocks_Issue(t *testing.T) {
+	issues := Mocks([]model.Mock{
+		{ID: 1, Method: "GET", Path: "/users", Namespace: "billing"},
+		{ID: 2, Method: "GET", Path: "/users", Namespace: "billing", Name: "users", RequestQueryMatchers: model.Matchers{{Key: "id", Value: 1}}},
+	})
+	assert.Equal(t, []Issue{{
+		Rule:      RuleShadowed,
+		MockID:    2,
+		Namespace: "billing",
+		Name:      "users",
+		Method:    "GET",
+		Path:      "/users",
+		Message:   ShadowedMock,
+		RelatedID: 1,
+	}}, issues)
+}
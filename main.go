@@ -1,19 +1,305 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
-	"log"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/rromanowicz/mockery/server"
+	"github.com/rromanowicz/mockery/har"
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/openapi"
+	"github.com/rromanowicz/mockery/pact"
+	"github.com/rromanowicz/mockery/posting"
+	"github.com/rromanowicz/mockery/postman"
+	"github.com/rromanowicz/mockery/snippet"
+	"github.com/rromanowicz/mockery/util"
+	"github.com/rromanowicz/mockery/wiremock"
 )
 
 func main() {
-	serverPort := flag.Int("port", 0, "Server port.")
-	serverDB := flag.String("db", "", "Database type.")
-	flag.Parse()
+	os.Exit(run(os.Args[1:]))
+}
+
+// runOpenAPI converts an OpenAPI 3 document into a mock file that can be placed in the import directory.
+func runOpenAPI(flags *flag.FlagSet, args []string) int {
+	paths := flags.String("paths", openapi.PathsRegex, "Templated paths: 'regex' or 'example'.")
+	out := flags.String("o", "", "Output file (.json, .yaml or .yml). Defaults to JSON on stdout.")
+	flags.Parse(args)
+	if flags.NArg() != 1 || !openapi.IsValidPaths(*paths) {
+		flags.Usage()
+		return exitUsage
+	}
+
+	contents, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	results, err := openapi.Read(contents, openapi.Options{Paths: *paths, File: flags.Arg(0)})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return writeMocks(results, *out)
+}
+
+// writeMocks writes successfully converted mocks to out, or as JSON to stdout. Failures are reported on stderr.
+func writeMocks(results []model.ImportResult, out string) int {
+	var mocks []model.Mock
+	for _, result := range results {
+		if len(result.Warnings) != 0 {
+			fmt.Fprintf(os.Stderr, "WARNING %s %v\n", result.File, result.Warnings)
+		}
+		if result.Status == model.ImportOK {
+			mocks = append(mocks, *result.Mock)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s %s %v\n", result.Status, result.File, result.Errors)
+		}
+	}
+
+	format := model.FormatJSON
+	if len(out) != 0 {
+		format = util.FormatOf(out)
+	}
+	if len(format) == 0 {
+		fmt.Fprintf(os.Stderr, "%s [%s]\n", model.UnsupportedFormat, out)
+		return exitUsage
+	}
+	data, err := util.MarshalMocks(mocks, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	if len(out) == 0 {
+		os.Stdout.Write(data)
+		return exitOK
+	}
+	if err = os.WriteFile(out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	fmt.Fprintf(os.Stderr, "Generated %d mocks [%s]\n", len(mocks), out)
+	return exitOK
+}
+
+// runPostman converts the requests and saved examples of a Postman v2.1 collection into a mock file.
+func runPostman(flags *flag.FlagSet, args []string) int {
+	out := flags.String("o", "", "Output file (.json, .yaml or .yml). Defaults to JSON on stdout.")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	contents, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	results, err := postman.Read(flags.Arg(0), contents)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return writeMocks(results, *out)
+}
+
+// runPosting converts Posting request files, or all of them in a directory, into a mock file.
+func runPosting(flags *flag.FlagSet, args []string) int {
+	out := flags.String("o", "", "Output file (.json, .yaml or .yml). Defaults to JSON on stdout.")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var results []model.ImportResult
+	for _, path := range flags.Args() {
+		fileResults, err := posting.ReadDir(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+		results = append(results, fileResults...)
+	}
+	return writeMocks(results, *out)
+}
+
+// runHAR converts the entries of a HAR file into a mock file.
+func runHAR(flags *flag.FlagSet, args []string) int {
+	out := flags.String("o", "", "Output file (.json, .yaml or .yml). Defaults to JSON on stdout.")
+	body := flags.Bool("body", true, "Match JSON request bodies.")
+	query := flags.Bool("query", true, "Match query parameters.")
+	headers := flags.String("headers", "", "Comma separated request headers to match.")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
 
-	err := server.StartMockServer(serverPort, serverDB)
+	options := har.Options{Body: *body, Query: *query}
+	if len(*headers) != 0 {
+		options.Headers = strings.Split(*headers, ",")
+	}
+	contents, err := os.ReadFile(flags.Arg(0))
 	if err != nil {
-		log.Panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	results, err := har.Read(flags.Arg(0), contents, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	return writeMocks(results, *out)
+}
+
+// runPact converts the interactions of pact files into a mock file.
+func runPact(flags *flag.FlagSet, args []string) int {
+	out := flags.String("o", "", "Output file (.json, .yaml or .yml). Defaults to JSON on stdout.")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var results []model.ImportResult
+	for _, file := range flags.Args() {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+		fileResults, err := pact.Read(file, contents)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s [%s]\n", err.Error(), file)
+			return exitFailed
+		}
+		results = append(results, fileResults...)
+	}
+	return writeMocks(results, *out)
+}
+
+// runWireMock converts WireMock mapping files, or all of them in a directory, into a mock file.
+// With -export mock files are converted into a WireMock mappings file instead.
+func runWireMock(flags *flag.FlagSet, args []string) int {
+	out := flags.String("o", "", "Output file. Defaults to stdout.")
+	export := flags.Bool("export", false, "Convert mock files into WireMock mappings.")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if *export {
+		return exportWireMock(flags.Args(), *out)
+	}
+	var results []model.ImportResult
+	for _, path := range flags.Args() {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || filepath.Ext(file) != ".json" {
+				return err
+			}
+			contents, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			fileResults, err := wiremock.Read(file, contents)
+			if err != nil {
+				return fmt.Errorf("%w [%s]", err, file)
+			}
+			results = append(results, fileResults...)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailed
+		}
+	}
+	return writeMocks(results, *out)
+}
+
+func exportWireMock(paths []string, out string) int {
+	mocks, err := readMockFiles(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	mappings := wiremock.FromMocks(mocks)
+	for _, mapping := range mappings.Mappings {
+		if unsupported, ok := mapping.Metadata[wiremock.UnsupportedMetadata]; ok {
+			fmt.Fprintf(os.Stderr, "WARNING %s %s %v\n", mapping.Request.Method, mapping.Request.URLPath+mapping.Request.URLPathPattern, unsupported)
+		}
+	}
+	data, _ := json.MarshalIndent(mappings, "", "  ")
+	if len(out) == 0 {
+		os.Stdout.Write(data)
+		return exitOK
+	}
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	fmt.Fprintf(os.Stderr, "Generated %d mappings [%s]\n", len(mappings.Mappings), out)
+	return exitOK
+}
+
+// readMockFiles reads mock files, and mock directories with their folder defaults. Failures are reported on stderr.
+func readMockFiles(paths []string) ([]model.Mock, error) {
+	var results []model.ImportResult
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			dirResults, err := util.Import(path, model.ImportOptions{Folders: model.FoldersIgnored})
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, dirResults...)
+			continue
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, util.ReadMocks(path, contents, model.ImportDefaults{})...)
+	}
+	var mocks []model.Mock
+	for _, result := range results {
+		if result.Status == model.ImportOK {
+			mocks = append(mocks, *result.Mock)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s %s %v\n", result.Status, result.File, result.Errors)
+		}
+	}
+	return mocks, nil
+}
+
+// runSnippet prints curl commands or Go code sending requests matched by mocks of mock files.
+func runSnippet(flags *flag.FlagSet, args []string) int {
+	format := flags.String("format", snippet.FormatCurl, "Snippet format (curl or go).")
+	baseURL := flags.String("url", "http://localhost:8080", "Base url of the mock server.")
+	flags.Parse(args)
+	if flags.NArg() == 0 || (*format != snippet.FormatCurl && *format != snippet.FormatGo) {
+		flags.Usage()
+		return exitUsage
+	}
+
+	mocks, err := readMockFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailed
+	}
+	var snippets []snippet.Snippet
+	for _, mock := range mocks {
+		snippets = append(snippets, snippet.For(mock, snippet.Options{BaseURL: *baseURL}))
 	}
+	os.Stdout.WriteString(snippet.Text(snippets, *format))
+	return exitOK
 }
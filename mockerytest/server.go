@@ -0,0 +1,198 @@
+// Package mockerytest runs isolated mock servers within Go tests.
+//
+//	srv := mockerytest.NewServer(t)
+//	persons := srv.Stub(mockerytest.Mock().Get("/persons").WithQuery("id", "1").Returns(200, `{"name": "John"}`))
+//	// call srv.URL + "/persons?id=1" from the code under test
+//	srv.Verify(persons[0], 1)
+package mockerytest
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rromanowicz/mockery/client"
+	"github.com/rromanowicz/mockery/context"
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/server"
+)
+
+// Server is a mock server backed by its own in-memory database and journal.
+type Server struct {
+	// URL is the base URL of the server, e.g. http://127.0.0.1:41234.
+	URL string
+
+	t      testing.TB
+	ctx    context.Context
+	server *httptest.Server
+	closed sync.Once
+}
+
+type options struct {
+	config model.Config
+	mocks  []*client.MockBuilder
+}
+
+type Option func(*options)
+
+// WithConfig adjusts the configuration before the server is started. The database is always in memory.
+func WithConfig(fn func(config *model.Config)) Option {
+	return func(o *options) {
+		fn(&o.config)
+	}
+}
+
+// WithNamespaces configures how the namespace of requests is selected.
+func WithNamespaces(namespaces model.Namespaces) Option {
+	return func(o *options) {
+		o.config.Namespaces = namespaces
+	}
+}
+
+// WithImportDir imports the mock files of dir on start.
+func WithImportDir(dir string) Option {
+	return func(o *options) {
+		o.config.ImportDir = dir
+		o.config.AutoImport = true
+	}
+}
+
+// WithMocks stubs mocks on start.
+func WithMocks(mocks ...*client.MockBuilder) Option {
+	return func(o *options) {
+		o.mocks = append(o.mocks, mocks...)
+	}
+}
+
+// NewServer starts a mock server that is closed when the test and its subtests complete.
+func NewServer(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.config.DBType = model.InMemory
+	if err := o.config.Validate(); err != nil {
+		t.Fatalf("mockerytest: %s", err.Error())
+	}
+
+	ctx, handler, err := server.NewHandler(&o.config)
+	if err != nil {
+		t.Fatalf("mockerytest: %s", err.Error())
+	}
+	s := &Server{t: t, ctx: ctx, server: httptest.NewServer(handler)}
+	s.URL = s.server.URL
+	t.Cleanup(s.Close)
+
+	s.Stub(o.mocks...)
+	return s
+}
+
+// Mock starts a mock description, e.g. Mock().Get("/persons").Returns(200, body).
+func Mock() *client.MockBuilder {
+	return client.Mock()
+}
+
+// Client returns an admin API client of the server.
+func (s *Server) Client() *client.Client {
+	return client.New(s.URL)
+}
+
+// Close stops the server. It is called on test cleanup and may be called earlier.
+func (s *Server) Close() {
+	s.closed.Do(func() {
+		s.server.Close()
+		if err := s.ctx.Close(); err != nil {
+			s.t.Errorf("mockerytest: %v", err)
+		}
+	})
+}
+
+// Stub validates and stores mocks, failing the test on invalid ones.
+// The stored mocks carry their ids, so they can be verified later on.
+func (s *Server) Stub(mocks ...*client.MockBuilder) []model.Mock {
+	s.t.Helper()
+	stored := make([]model.Mock, 0, len(mocks))
+	for _, builder := range mocks {
+		mock, err := builder.Build()
+		if err != nil {
+			s.t.Fatalf("mockerytest: %s %s: %s", mock.Method, mock.Path+mock.RegexPath, err.Error())
+		}
+		if valid, errs := s.ctx.MockService.Validate(mock); !valid {
+			s.t.Fatalf("mockerytest: %s %s: %s", mock.Method, mock.Path+mock.RegexPath, strings.Join(errs, " "))
+		}
+		mock, err = s.ctx.MockService.Add(mock)
+		if err != nil {
+			s.t.Fatalf("mockerytest: %s", err.Error())
+		}
+		stored = append(stored, mock)
+	}
+	return stored
+}
+
+// Mocks lists the mocks of the default namespace.
+func (s *Server) Mocks() []model.Mock {
+	s.t.Helper()
+	mocks, err := s.ctx.MockService.List(model.Selector{})
+	if err != nil {
+		s.t.Fatalf("mockerytest: %s", err.Error())
+	}
+	return mocks
+}
+
+// Reset removes the mocks and journal entries of the default namespace.
+func (s *Server) Reset() {
+	s.t.Helper()
+	if _, err := s.ctx.MockService.Reset(""); err != nil {
+		s.t.Fatalf("mockerytest: %s", err.Error())
+	}
+	s.ctx.Journal.Clear(model.JournalFilter{})
+}
+
+// Journal lists the requests received in the default namespace, oldest first.
+func (s *Server) Journal() []model.JournalEntry {
+	return s.ctx.Journal.List(model.JournalFilter{})
+}
+
+// Requests lists the requests answered by mock.
+func (s *Server) Requests(mock model.Mock) []model.JournalEntry {
+	var requests []model.JournalEntry
+	for _, entry := range s.ctx.Journal.List(model.JournalFilter{Namespace: mock.Namespace, SessionID: mock.SessionID}) {
+		if entry.MockID == mock.ID {
+			requests = append(requests, entry)
+		}
+	}
+	return requests
+}
+
+// Verify reports an error unless mock answered exactly times requests.
+func (s *Server) Verify(mock model.Mock, times int) bool {
+	s.t.Helper()
+	if count := len(s.Requests(mock)); count != times {
+		s.t.Errorf("mockerytest: %s answered %d requests, expected %d", describe(mock), count, times)
+		return false
+	}
+	return true
+}
+
+// VerifyNoUnmatched reports an error for every request of the default namespace no mock answered.
+func (s *Server) VerifyNoUnmatched() bool {
+	s.t.Helper()
+	ok := true
+	for _, entry := range s.Journal() {
+		if entry.MockID == 0 {
+			s.t.Errorf("mockerytest: unmatched request %s %s", entry.Request.Method, entry.Request.URL)
+			ok = false
+		}
+	}
+	return ok
+}
+
+func describe(mock model.Mock) string {
+	if len(mock.Name) != 0 {
+		return fmt.Sprintf("mock %d [%s]", mock.ID, mock.Name)
+	}
+	return fmt.Sprintf("mock %d [%s %s]", mock.ID, mock.Method, mock.Path+mock.RegexPath)
+}
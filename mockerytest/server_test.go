@@ -0,0 +1,143 @@
+package mockerytest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(t)
+	mocks := srv.Stub(
+		Mock().Get("/persons").WithQuery("id", "1").Returns(200, `{"name": "John"}`),
+		Mock().Post("/persons").WithBody("$.name", "Jane").Returns(201, map[string]any{"id": 2}),
+		Mock().Get("/").WithRegexPath("^/persons/[0-9]+$").Named("person").Returns(404, nil),
+	)
+	assert.Len(t, mocks, 3)
+	assert.NotZero(t, mocks[0].ID)
+	assert.Len(t, srv.Mocks(), 3)
+
+	status, body := get(t, srv.URL+"/persons?id=1")
+	assert.Equal(t, 200, status)
+	assert.JSONEq(t, `{"name": "John"}`, body)
+
+	resp, err := http.Post(srv.URL+"/persons", "application/json", strings.NewReader(`{"name": "Jane"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+	resp.Body.Close()
+
+	status, _ = get(t, srv.URL+"/persons/7")
+	assert.Equal(t, 404, status)
+	status, _ = get(t, srv.URL+"/persons/7")
+	assert.Equal(t, 404, status)
+
+	assert.Len(t, srv.Journal(), 4)
+	assert.True(t, srv.Verify(mocks[0], 1))
+	assert.True(t, srv.Verify(mocks[1], 1))
+	assert.True(t, srv.Verify(mocks[2], 2))
+	assert.Equal(t, "/persons/7", srv.Requests(mocks[2])[0].Request.URL)
+	assert.True(t, srv.VerifyNoUnmatched())
+
+	srv.Reset()
+	assert.Empty(t, srv.Mocks())
+	assert.Empty(t, srv.Journal())
+}
+
+func TestServer_Verify(t *testing.T) {
+	srv := NewServer(t, WithMocks(Mock().Get("/persons").Returns(200, nil)))
+	get(t, srv.URL+"/unknown")
+
+	recorder := &recorder{TB: t}
+	srv.t = recorder
+	assert.False(t, srv.Verify(srv.Mocks()[0], 1))
+	assert.False(t, srv.VerifyNoUnmatched())
+	assert.Equal(t, []string{
+		"mockerytest: mock 1 [GET /persons] answered 0 requests, expected 1",
+		"mockerytest: unmatched request GET /unknown",
+	}, recorder.errors)
+}
+
+// recorder collects the errors reported to a test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServer_Isolated(t *testing.T) {
+	first := NewServer(t, WithMocks(Mock().Get("/persons").Returns(200, `{"server": 1}`)))
+	second := NewServer(t, WithMocks(Mock().Get("/persons").Returns(200, `{"server": 2}`)))
+
+	_, body := get(t, first.URL+"/persons")
+	assert.JSONEq(t, `{"server": 1}`, body)
+	_, body = get(t, second.URL+"/persons")
+	assert.JSONEq(t, `{"server": 2}`, body)
+	assert.Len(t, first.Journal(), 1)
+	assert.Len(t, second.Journal(), 1)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			status, _ := get(t, first.URL+"/persons")
+			assert.Equal(t, 200, status)
+		})
+	}
+	wg.Wait()
+	assert.True(t, first.Verify(first.Mocks()[0], 21))
+}
+
+func TestServer_IsolatedContracts(t *testing.T) {
+	document := filepath.Join(t.TempDir(), "openapi.yaml")
+	os.WriteFile(document, []byte(`
+openapi: 3.0.3
+info: { title: Orders, version: "1" }
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      responses:
+        '200': { description: OK }
+`), 0o644)
+	first := NewServer(t, WithConfig(func(config *model.Config) { config.Contract.Document = document }))
+	second := NewServer(t)
+
+	post := func(url string) int {
+		resp, err := http.Post(url+"/config", "application/json", strings.NewReader(`{"method": "GET", "path": "/orders", "responseStatus": 500}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, 400, post(first.URL))
+	assert.Equal(t, 201, post(second.URL))
+}
+
+func TestServer_Client(t *testing.T) {
+	srv := NewServer(t, WithMocks(Mock().Get("/persons").Returns(200, nil)))
+	mocks, err := srv.Client().List(t.Context(), model.Selector{})
+	assert.NoError(t, err)
+	assert.Equal(t, srv.Mocks(), mocks)
+}
@@ -2,29 +2,85 @@ package model
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 type Database string
 
 const (
-	SqLite                  Database = "SqLite"
-	Postgres                Database = "Postgres"
-	InMemory                Database = "InMemory"
-	ExportDir               string   = "./.export"
-	ImportDir               string   = "./.import"
-	defaultPort             int      = 8080
-	defaultConnStr          string   = ""
-	MissingConnectionString          = "Missing connection string"
-	UnsupportedDBType                = "unsupported dbType"
+	SqLite                   Database = "SqLite"
+	Postgres                 Database = "Postgres"
+	InMemory                 Database = "InMemory"
+	ExportDir                string   = "./.export"
+	ImportDir                string   = "./.import"
+	TLSDir                   string   = "./.tls"
+	defaultPort              int      = 8080
+	defaultJournalSize       int      = 1000
+	defaultWatchInterval              = 2 * time.Second
+	defaultShutdownTimeout            = 10 * time.Second
+	defaultTLSPort           int      = 8443
+	defaultConnStr           string   = ""
+	MissingConnectionString           = "Missing connection string"
+	UnsupportedDBType                 = "unsupported dbType"
+	InvalidDirectory                  = "Invalid directory. Must be a relative path within the configured directory."
+	UnsupportedImportFolders          = "unsupported importFolders"
+	RedactedValue                     = "*****"
+	IncompleteTLSKeyPair              = "Both tls.certFile and tls.keyFile must be provided"
+	ConflictingTLSPort                = "tls.port must differ from port"
+	UnsupportedClientAuth             = "unsupported tls.clientAuth"
+	ClientAuthNone                    = "none"
+	ClientAuthRequest                 = "request"
+	ClientAuthRequire                 = "require"
 )
 
 type Config struct {
-	DBType     Database `json:"dbType" yaml:"dbType"`
-	Port       int      `json:"port" yaml:"port"`
-	DBConfig   DBConfig `json:"dbConfig" yaml:"dbConfig"`
-	ExportDir  string   `json:"exportDir" yaml:"exportDir"`
-	ImportDir  string   `json:"importDir" yaml:"importDir"`
-	AutoImport bool     `json:"autoImport" yaml:"autoImport"`
+	DBType           Database      `json:"dbType" yaml:"dbType"`
+	Port             int           `json:"port" yaml:"port"`
+	DBConfig         DBConfig      `json:"dbConfig" yaml:"dbConfig"`
+	ExportDir        string        `json:"exportDir" yaml:"exportDir"`
+	ImportDir        string        `json:"importDir" yaml:"importDir"`
+	AutoImport       bool          `json:"autoImport" yaml:"autoImport"`
+	WatchImport      bool          `json:"watchImport" yaml:"watchImport"`
+	WatchInterval    time.Duration `json:"watchInterval" yaml:"watchInterval"`
+	ImportFolders    string        `json:"importFolders" yaml:"importFolders"`
+	ImportMode       string        `json:"importMode" yaml:"importMode"`
+	Namespaces       Namespaces    `json:"namespaces" yaml:"namespaces"`
+	JournalSize      int           `json:"journalSize" yaml:"journalSize"`
+	Contract         Contract      `json:"contract" yaml:"contract"`
+	ShutdownTimeout  time.Duration `json:"shutdownTimeout" yaml:"shutdownTimeout"`
+	ExportOnShutdown bool          `json:"exportOnShutdown" yaml:"exportOnShutdown"`
+	TLS              TLS           `json:"tls" yaml:"tls"`
+}
+
+// TLS serves HTTPS on its own port next to HTTP. Without certFile and keyFile a certificate for hosts is generated
+// into dir, signed by a self-signed CA which is created there once, so that clients can trust it across restarts.
+// ClientAuth requests or requires client certificates, verified against clientCaFile or the CA of dir.
+type TLS struct {
+	Enabled      bool     `json:"enabled" yaml:"enabled"`
+	Port         int      `json:"port" yaml:"port"`
+	CertFile     string   `json:"certFile" yaml:"certFile"`
+	KeyFile      string   `json:"keyFile" yaml:"keyFile"`
+	Dir          string   `json:"dir" yaml:"dir"`
+	Hosts        []string `json:"hosts" yaml:"hosts"`
+	ClientAuth   string   `json:"clientAuth" yaml:"clientAuth"`
+	ClientCAFile string   `json:"clientCaFile" yaml:"clientCaFile"`
+}
+
+// Contract is an OpenAPI document that requests and mocks are validated against.
+// Both are validated if neither is selected.
+type Contract struct {
+	Document  string `json:"document" yaml:"document"`
+	Requests  bool   `json:"requests" yaml:"requests"`
+	Responses bool   `json:"responses" yaml:"responses"`
+}
+
+// Namespaces configures how the namespace of an incoming request is selected.
+// Path prefix takes precedence over the header, the header over the host name.
+type Namespaces struct {
+	Header     string            `json:"header" yaml:"header"`
+	Hosts      map[string]string `json:"hosts" yaml:"hosts"`
+	PathPrefix string            `json:"pathPrefix" yaml:"pathPrefix"`
 }
 
 type DBConfig struct {
@@ -46,6 +102,42 @@ func (c *Config) Validate() error {
 	if c.Port == 0 {
 		c.Port = defaultPort
 	}
+	if len(c.ImportFolders) == 0 {
+		c.ImportFolders = FoldersAsTags
+	} else if !IsValidImportFolders(c.ImportFolders) {
+		return fmt.Errorf("[%s] - %s", c.ImportFolders, UnsupportedImportFolders)
+	}
+	if len(c.ImportMode) == 0 {
+		c.ImportMode = ImportCreate
+	} else if !IsValidImportMode(c.ImportMode) {
+		return fmt.Errorf("[%s] - %s", c.ImportMode, UnsupportedImportMode)
+	}
+	if c.WatchInterval <= 0 {
+		c.WatchInterval = defaultWatchInterval
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if c.JournalSize <= 0 {
+		c.JournalSize = defaultJournalSize
+	}
+	if len(c.Contract.Document) != 0 && !c.Contract.Requests && !c.Contract.Responses {
+		c.Contract.Requests, c.Contract.Responses = true, true
+	}
+	if len(c.Namespaces.Header) == 0 {
+		c.Namespaces.Header = DefaultNamespaceHeader
+	}
+	if len(c.Namespaces.PathPrefix) != 0 {
+		c.Namespaces.PathPrefix = "/" + strings.Trim(c.Namespaces.PathPrefix, "/")
+	}
+	for host, namespace := range c.Namespaces.Hosts {
+		if !IsValidNamespace(namespace) {
+			return fmt.Errorf("[%s] - %s", host, InvalidNamespace)
+		}
+	}
+	if err := c.TLS.validate(c.Port); err != nil {
+		return err
+	}
 	switch c.DBType {
 	case SqLite:
 		if len(c.DBConfig.SqLite.ConnectionString) == 0 {
@@ -62,3 +154,42 @@ func (c *Config) Validate() error {
 	}
 	return nil
 }
+
+// Redacted returns a copy of the config with connection strings, which may contain credentials, masked.
+func (c Config) Redacted() Config {
+	for _, params := range []*DBParams{&c.DBConfig.SqLite, &c.DBConfig.Postgres} {
+		if len(params.ConnectionString) != 0 {
+			params.ConnectionString = RedactedValue
+		}
+	}
+	return c
+}
+
+func (t *TLS) validate(port int) error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.Port == 0 {
+		t.Port = defaultTLSPort
+	}
+	if t.Port == port {
+		return fmt.Errorf("[%d] - %s", t.Port, ConflictingTLSPort)
+	}
+	if (len(t.CertFile) == 0) != (len(t.KeyFile) == 0) {
+		return fmt.Errorf("[%s%s] - %s", t.CertFile, t.KeyFile, IncompleteTLSKeyPair)
+	}
+	switch t.ClientAuth {
+	case "":
+		t.ClientAuth = ClientAuthNone
+	case ClientAuthNone, ClientAuthRequest, ClientAuthRequire:
+	default:
+		return fmt.Errorf("[%s] - %s", t.ClientAuth, UnsupportedClientAuth)
+	}
+	if len(t.Dir) == 0 {
+		t.Dir = TLSDir
+	}
+	if len(t.Hosts) == 0 {
+		t.Hosts = []string{"localhost", "127.0.0.1", "::1"}
+	}
+	return nil
+}
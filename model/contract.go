@@ -0,0 +1,8 @@
+package model
+
+// ContractResult is the outcome of validating a request against the API contract.
+type ContractResult struct {
+	Operation string   `json:"operation"`
+	Valid     bool     `json:"valid"`
+	Errors    []string `json:"errors,omitempty"`
+}
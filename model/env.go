@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	EnvPrefix     = "MOCKERY_"
+	InvalidEnvVar = "Invalid environment variable"
+)
+
+// ApplyEnv sets config fields from environment variables named after their yaml keys, e.g. 'dbConfig.sqlite.connStr'
+// from MOCKERY_DB_CONFIG_SQLITE_CONN_STR. Lists are given as 'a,b', maps as 'key=value,key=value' and durations as '2s'.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	return applyEnv(reflect.ValueOf(c).Elem(), EnvPrefix, lookup)
+}
+
+func applyEnv(value reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		name := prefix + envName(strings.Split(field.Tag.Get("yaml"), ",")[0])
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value.Field(i), name+"_", lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		env, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setEnv(value.Field(i), env); err != nil {
+			return fmt.Errorf("[%s] - %s. %w", name, InvalidEnvVar, err)
+		}
+	}
+	return nil
+}
+
+// envName converts a camel case yaml key to upper snake case.
+func envName(key string) string {
+	var name strings.Builder
+	for i, r := range key {
+		if i != 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(key[i-1])) {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+	}
+	return name.String()
+}
+
+func setEnv(field reflect.Value, env string) error {
+	if field.Type() == reflect.TypeFor[time.Duration]() {
+		duration, err := time.ParseDuration(env)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(env)
+	case reflect.Int:
+		number, err := strconv.Atoi(env)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(number))
+	case reflect.Bool:
+		flag, err := strconv.ParseBool(env)
+		if err != nil {
+			return err
+		}
+		field.SetBool(flag)
+	case reflect.Slice:
+		var values []string
+		for value := range strings.SplitSeq(env, ",") {
+			values = append(values, strings.TrimSpace(value))
+		}
+		field.Set(reflect.ValueOf(values))
+	case reflect.Map:
+		entries := map[string]string{}
+		for entry := range strings.SplitSeq(env, ",") {
+			key, value, ok := strings.Cut(entry, "=")
+			if !ok {
+				return fmt.Errorf("expected 'key=value' entries, got [%s]", entry)
+			}
+			entries[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		field.Set(reflect.ValueOf(entries))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
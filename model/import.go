@@ -0,0 +1,170 @@
+package model
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	ImportOK     = "OK"
+	ImportFailed = "FAILED"
+
+	// DefaultsFile (.json, .yaml or .yml) holds ImportDefaults applied to all mocks in its folder and subfolders.
+	DefaultsFile = "_defaults"
+
+	FormatJSON        = "json"
+	FormatYAML        = "yaml"
+	UnsupportedFormat = "Unsupported format"
+
+	FoldersAsTags      = "tag"
+	FoldersAsNamespace = "namespace"
+	FoldersIgnored     = "none"
+
+	// ImportCreate saves every imported mock, ImportUpsert updates mocks with the same natural key
+	// and ImportSync additionally deletes mocks missing in the import.
+	ImportCreate = "create"
+	ImportUpsert = "upsert"
+	ImportSync   = "sync"
+
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionUnchanged = "unchanged"
+	ActionDeleted   = "deleted"
+
+	UnsupportedImportMode   = "Unsupported import mode"
+	DuplicateNaturalKey     = "Duplicate mock. Another imported mock has the same name or method, path and matchers."
+	UnsupportedResponseBody = "Unsupported response body. Only JSON objects can be mocked."
+)
+
+type ImportOptions struct {
+	Namespace string
+	// Folders decides what the folder of an imported file is turned into.
+	Folders string
+	Mode    string
+	// DryRun reports what the import would do without changing stored mocks.
+	DryRun bool
+	// Dir is the imported subdirectory of the import directory. Sources of its mocks are prefixed with it
+	// and sync deletes only mocks with a source below it.
+	Dir string
+	// Files are the sources of imported single files. If set, sync deletes only mocks imported from them.
+	Files []string
+}
+
+// Synced reports whether a stored mock imported from source is in the scope of a sync import.
+// Mocks without a source were not imported and are never synced.
+func (o ImportOptions) Synced(source string) bool {
+	switch {
+	case len(source) == 0:
+		return false
+	case len(o.Files) != 0:
+		return slices.Contains(o.Files, source)
+	default:
+		return len(o.Dir) == 0 || strings.HasPrefix(source, o.Dir+"/")
+	}
+}
+
+func IsValidImportMode(mode string) bool {
+	return slices.Contains([]string{ImportCreate, ImportUpsert, ImportSync}, mode)
+}
+
+func IsValidImportFolders(folders string) bool {
+	return slices.Contains([]string{FoldersAsTags, FoldersAsNamespace, FoldersIgnored}, folders)
+}
+
+type ImportResult struct {
+	File   string   `json:"file,omitempty"`
+	Line   int      `json:"line,omitempty"`
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+	// Warnings report parts of converted files which were not imported.
+	Warnings []string `json:"warnings,omitempty"`
+	Action   string   `json:"action,omitempty"`
+	MockID   int64    `json:"mockId,omitempty"`
+	Key      string   `json:"key,omitempty"`
+	Mock     *Mock    `json:"-"`
+}
+
+// ImportDefaults are shared properties of mocks kept in one folder.
+type ImportDefaults struct {
+	Namespace             string   `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	PathPrefix            string   `json:"pathPrefix,omitempty" yaml:"pathPrefix,omitempty"`
+	Tags                  Tags     `json:"tags,omitempty" yaml:"tags,omitempty"`
+	RequestHeaderMatchers Matchers `json:"requestHeaderMatchers,omitempty" yaml:"requestHeaderMatchers,omitempty"`
+	RequestQueryMatchers  Matchers `json:"requestQueryMatchers,omitempty" yaml:"requestQueryMatchers,omitempty"`
+}
+
+// Merge returns defaults of a subfolder. Path prefixes are joined, tags and matchers are added
+// and the namespace is replaced.
+func (d ImportDefaults) Merge(child ImportDefaults) ImportDefaults {
+	merged := ImportDefaults{
+		Namespace:             d.Namespace,
+		PathPrefix:            joinPath(d.PathPrefix, child.PathPrefix),
+		Tags:                  appendTags(slices.Clone(d.Tags), child.Tags...),
+		RequestHeaderMatchers: mergeMatchers(child.RequestHeaderMatchers, d.RequestHeaderMatchers),
+		RequestQueryMatchers:  mergeMatchers(child.RequestQueryMatchers, d.RequestQueryMatchers),
+	}
+	if len(child.Namespace) != 0 {
+		merged.Namespace = child.Namespace
+	}
+	return merged
+}
+
+// Apply fills in the defaults. Values already present in the mock take precedence.
+func (d ImportDefaults) Apply(mock *Mock) {
+	if len(mock.Namespace) == 0 {
+		mock.Namespace = d.Namespace
+	}
+	if len(d.PathPrefix) != 0 {
+		if len(mock.Path) != 0 {
+			mock.Path = joinPath(d.PathPrefix, mock.Path)
+		}
+		if len(mock.RegexPath) != 0 {
+			prefix := regexp.QuoteMeta(strings.TrimSuffix(d.PathPrefix, "/"))
+			if rest, anchored := strings.CutPrefix(mock.RegexPath, "^"); anchored {
+				mock.RegexPath = "^" + prefix + rest
+			} else {
+				mock.RegexPath = prefix + mock.RegexPath
+			}
+		}
+	}
+	mock.Tags = appendTags(mock.Tags, d.Tags...)
+	mock.RequestHeaderMatchers = mergeMatchers(mock.RequestHeaderMatchers, d.RequestHeaderMatchers)
+	mock.RequestQueryMatchers = mergeMatchers(mock.RequestQueryMatchers, d.RequestQueryMatchers)
+}
+
+func joinPath(prefix string, path string) string {
+	if len(prefix) == 0 {
+		return path
+	}
+	if len(path) == 0 {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func appendTags(tags Tags, added ...string) Tags {
+	for _, tag := range added {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// mergeMatchers adds defaults for keys missing in matchers.
+func mergeMatchers(matchers Matchers, defaults Matchers) Matchers {
+	merged := slices.Clone(matchers)
+	for _, matcher := range defaults {
+		if !slices.ContainsFunc(merged, func(m Matcher) bool { return m.Key == matcher.Key }) {
+			merged = append(merged, matcher)
+		}
+	}
+	return merged
+}
+
+// MockFile is an uploaded mock file or archive of mock files.
+type MockFile struct {
+	Name     string
+	Contents []byte
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+type JournalEntry struct {
+	ID        int64           `json:"id"`
+	Time      time.Time       `json:"time"`
+	Namespace string          `json:"namespace,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	MockID    int64           `json:"mockId,omitempty"`
+	Request   JournalRequest  `json:"request"`
+	Response  JournalResponse `json:"response"`
+	Contract  *ContractResult `json:"contract,omitempty"`
+}
+
+type JournalRequest struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	ClientCert *ClientCert `json:"clientCert,omitempty"`
+}
+
+type JournalResponse struct {
+	Status int    `json:"status"`
+	Body   string `json:"body,omitempty"`
+}
+
+// JournalFilter selects journal entries of a namespace, optionally narrowed down to a single session.
+type JournalFilter struct {
+	Namespace string
+	SessionID string
+}
+
+func (f JournalFilter) Matches(entry JournalEntry) bool {
+	if f.Namespace != entry.Namespace {
+		return false
+	}
+	return len(f.SessionID) == 0 || f.SessionID == entry.SessionID
+}
@@ -0,0 +1,253 @@
+package model
+
+import (
+	"cmp"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/theory/jsonpath"
+)
+
+const (
+	MatcherQuery   = "query"
+	MatcherHeader  = "header"
+	MatcherBody    = "body"
+	MatcherCert    = "cert"
+	CertCommonName = "cn"
+	CertSAN        = "san"
+	CertIssuer     = "issuer"
+)
+
+// CertMatcherKeys are the client certificate fields cert matchers compare with.
+var CertMatcherKeys = []string{CertCommonName, CertSAN, CertIssuer}
+
+// ClientCert is the identity of the verified client certificate of a request. Issuer is the common name of the issuer.
+type ClientCert struct {
+	CommonName string   `json:"commonName,omitempty"`
+	SANs       []string `json:"sans,omitempty"`
+	Issuer     string   `json:"issuer,omitempty"`
+}
+
+// ClientCertOf returns the identity of certificate. Its SANs are DNS names, email addresses, IP addresses and URIs.
+func ClientCertOf(certificate *x509.Certificate) *ClientCert {
+	cert := &ClientCert{CommonName: certificate.Subject.CommonName, Issuer: certificate.Issuer.CommonName}
+	cert.SANs = append(cert.SANs, certificate.DNSNames...)
+	cert.SANs = append(cert.SANs, certificate.EmailAddresses...)
+	for _, ip := range certificate.IPAddresses {
+		cert.SANs = append(cert.SANs, ip.String())
+	}
+	for _, uri := range certificate.URIs {
+		cert.SANs = append(cert.SANs, uri.String())
+	}
+	return cert
+}
+
+// MatchReport explains which mock would answer a request. Candidates are the mocks on the route of the
+// request in match order, and Mock is the first of them accepting it, if any.
+type MatchReport struct {
+	Namespace  string           `json:"namespace,omitempty"`
+	SessionID  string           `json:"sessionId,omitempty"`
+	Method     string           `json:"method"`
+	Path       string           `json:"path"`
+	Mock       *Mock            `json:"mock,omitempty"`
+	Candidates []MatchCandidate `json:"candidates"`
+	Contract   *ContractResult  `json:"contract,omitempty"`
+	Message    string           `json:"message,omitempty"`
+}
+
+type MatchCandidate struct {
+	Mock     Mock            `json:"mock"`
+	Matched  bool            `json:"matched"`
+	Matchers []MatcherResult `json:"matchers"`
+}
+
+// MatcherResult is the outcome of a single matcher. Actual holds the request values the matcher was compared with.
+type MatcherResult struct {
+	Type     string   `json:"type"`
+	Key      string   `json:"key"`
+	Expected any      `json:"expected"`
+	Actual   []string `json:"actual"`
+	Matched  bool     `json:"matched"`
+}
+
+// MatchesRoute reports whether the mock stubs requests with method to path.
+func (m Mock) MatchesRoute(method string, path string) bool {
+	if m.Method != method {
+		return false
+	}
+	if len(m.Path) != 0 {
+		return m.Path == path
+	}
+	pattern := RegexPath(m.RegexPath).Compile()
+	return pattern != nil && pattern.MatchString(path)
+}
+
+// MatchesRequest reports whether the query, header and body matchers of the mock accept a request.
+func (m Mock) MatchesRequest(query url.Values, header http.Header, body []byte) bool {
+	return m.RequestBodyMatchers.MatchBody(body) &&
+		m.RequestQueryMatchers.MatchQuery(query) &&
+		m.RequestHeaderMatchers.MatchHeader(header)
+}
+
+// ExplainRequest returns the outcome of each query, header and body matcher of the mock for a request.
+func (m Mock) ExplainRequest(query url.Values, header http.Header, body []byte) []MatcherResult {
+	results := []MatcherResult{}
+	for _, matcher := range m.RequestQueryMatchers {
+		results = append(results, resultOf(MatcherQuery, matcher, query[matcher.Key], matchQuery(matcher, query)))
+	}
+	for _, matcher := range m.RequestHeaderMatchers {
+		results = append(results, resultOf(MatcherHeader, matcher, header.Values(matcher.Key), matchHeader(matcher, header)))
+	}
+	for _, matcher := range m.RequestBodyMatchers {
+		var selected []string
+		if len(body) != 0 {
+			selected = selectBody(matcher, body)
+		}
+		results = append(results, resultOf(MatcherBody, matcher, selected, containsValue(matcher, selected)))
+	}
+	return results
+}
+
+// MatchOrder orders mocks the way requests are matched against them: session mocks before global ones,
+// mocks with cert matchers before a fallback without them, and otherwise by id.
+func MatchOrder(a Mock, b Mock) int {
+	return cmp.Or(
+		cmp.Compare(len(b.SessionID), len(a.SessionID)),
+		cmp.Compare(min(len(b.RequestCertMatchers), 1), min(len(a.RequestCertMatchers), 1)),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// MatchesClientCert reports whether the cert matchers of the mock accept the client certificate of a request.
+// Without a certificate only mocks without cert matchers match.
+func (m Mock) MatchesClientCert(cert *ClientCert) bool {
+	for _, matcher := range m.RequestCertMatchers {
+		if !containsValue(matcher, certValues(matcher.Key, cert)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExplainClientCert returns the outcome of each cert matcher of the mock for a client certificate.
+func (m Mock) ExplainClientCert(cert *ClientCert) []MatcherResult {
+	results := []MatcherResult{}
+	for _, matcher := range m.RequestCertMatchers {
+		values := certValues(matcher.Key, cert)
+		results = append(results, resultOf(MatcherCert, matcher, values, containsValue(matcher, values)))
+	}
+	return results
+}
+
+func certValues(key string, cert *ClientCert) []string {
+	if cert == nil {
+		return nil
+	}
+	switch strings.ToLower(key) {
+	case CertCommonName:
+		return []string{cert.CommonName}
+	case CertSAN:
+		return cert.SANs
+	case CertIssuer:
+		return []string{cert.Issuer}
+	}
+	return nil
+}
+
+func resultOf(kind string, matcher Matcher, actual []string, matched bool) MatcherResult {
+	if actual == nil {
+		actual = []string{}
+	}
+	return MatcherResult{Type: kind, Key: matcher.Key, Expected: matcher.Value, Actual: actual, Matched: matched}
+}
+
+func (m Matchers) MatchQuery(query url.Values) bool {
+	if len(m) == 0 {
+		return true
+	}
+	if len(query) == 0 {
+		return false
+	}
+
+	for _, matcher := range m {
+		if !matchQuery(matcher, query) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchQuery(matcher Matcher, query url.Values) bool {
+	input := query.Get(matcher.Key)
+	return len(input) != 0 && fmt.Sprintf("%v", matcher.Value) == input
+}
+
+func (m Matchers) MatchBody(body []byte) bool {
+	if len(m) == 0 {
+		return true
+	}
+	if len(body) == 0 {
+		return false
+	}
+
+	for _, matcher := range m {
+		if !isPathMatching(matcher, body) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Matchers) MatchHeader(header http.Header) bool {
+	if len(m) == 0 {
+		return true
+	}
+	if len(header) == 0 {
+		return false
+	}
+
+	for _, matcher := range m {
+		if !matchHeader(matcher, header) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchHeader(matcher Matcher, header http.Header) bool {
+	headerValue := header.Get(matcher.Key)
+	return len(headerValue) != 0 && matcher.Value == headerValue
+}
+
+func isPathMatching(matcher Matcher, body []byte) bool {
+	return containsValue(matcher, selectBody(matcher, body))
+}
+
+func containsValue(matcher Matcher, values []string) bool {
+	return slices.ContainsFunc(values, func(value string) bool { return matcher.Value == value })
+}
+
+// selectBody returns the text of the body values selected by the JsonPath key of matcher.
+func selectBody(matcher Matcher, body []byte) []string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		log.Printf("Failed to marshal request body. %s", err.Error())
+	}
+	path, err := jsonpath.Parse(matcher.Key)
+	if err != nil {
+		log.Printf("Failed to parse JsonPath. %s", err.Error())
+		return nil
+	}
+
+	var selected []string
+	for _, node := range path.Select(value) {
+		selected = append(selected, fmt.Sprintf("%v", node))
+	}
+	return selected
+}
@@ -20,29 +20,42 @@ const (
 	InvalidBodyMatcherJSONPath = "Invalid BodyMatcher. Cannot parse key value as JsonPath."
 	InvalidQueryMatcher        = "Invalid QueryMatcher. Both values must be provided."
 	InvalidHeaderMatcher       = "Invalid HeaderMatcher. Both values must be provided."
+	InvalidCertMatcher         = "Invalid CertMatcher. Key must be 'cn', 'san' or 'issuer' and value must be provided."
 	InvalidPath                = "Invalid path. Either 'Path' or 'RegexPath' must be provided."
 	InvalidRegex               = "Invalid RegexPath."
+	InvalidTag                 = "Invalid tag. Tags can not be empty."
+	InvalidNamespace           = "Invalid namespace. Only letters, digits, '.', '_' and '-' are allowed."
 	InvalidValue               = "Invalid value"
 	CanNotBeEmpty              = "can not be empty"
 )
 
 type Mock struct {
-	ID                    int64    `json:"id"`
-	Method                string   `json:"method" validate:"notEmpty,httpMethod"`
-	Path                  string   `json:"path,omitempty"`
-	RegexPath             string   `json:"regexPath,omitempty"`
-	RequestHeaderMatchers Matchers `json:"requestHeaderMatchers,omitempty" gorm:"type:jsonb"`
-	RequestQueryMatchers  Matchers `json:"requestQueryMatchers,omitempty" gorm:"type:jsonb"`
-	RequestBodyMatchers   Matchers `json:"requestBodyMatchers,omitempty" gorm:"type:jsonb"`
-	ResponseStatus        int      `json:"responseStatus" validate:"httpStatus"`
-	ResponseBody          JSONB    `json:"responseBody" gorm:"type:jsonb"`
+	ID                    int64    `json:"id" yaml:"id"`
+	Namespace             string   `json:"namespace,omitempty" yaml:"namespace,omitempty" gorm:"not null;default:'';index"`
+	SessionID             string   `json:"sessionId,omitempty" yaml:"sessionId,omitempty" gorm:"not null;default:'';index"`
+	Name                  string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Description           string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags                  Tags     `json:"tags,omitempty" yaml:"tags,omitempty" gorm:"type:jsonb"`
+	Disabled              bool     `json:"disabled,omitempty" yaml:"disabled,omitempty" gorm:"not null;default:false"`
+	Source                string   `json:"source,omitempty" yaml:"source,omitempty" gorm:"not null;default:'';index"`
+	Method                string   `json:"method" yaml:"method" validate:"notEmpty,httpMethod"`
+	Path                  string   `json:"path,omitempty" yaml:"path,omitempty"`
+	RegexPath             string   `json:"regexPath,omitempty" yaml:"regexPath,omitempty"`
+	RequestHeaderMatchers Matchers `json:"requestHeaderMatchers,omitempty" yaml:"requestHeaderMatchers,omitempty" gorm:"type:jsonb"`
+	RequestQueryMatchers  Matchers `json:"requestQueryMatchers,omitempty" yaml:"requestQueryMatchers,omitempty" gorm:"type:jsonb"`
+	RequestBodyMatchers   Matchers `json:"requestBodyMatchers,omitempty" yaml:"requestBodyMatchers,omitempty" gorm:"type:jsonb"`
+	RequestCertMatchers   Matchers `json:"requestCertMatchers,omitempty" yaml:"requestCertMatchers,omitempty" gorm:"type:jsonb"`
+	ResponseStatus        int      `json:"responseStatus" yaml:"responseStatus" validate:"httpStatus"`
+	ResponseBody          JSONB    `json:"responseBody" yaml:"responseBody" gorm:"type:jsonb"`
 }
 
 type Matchers []Matcher
 
+type Tags []string
+
 type Matcher struct {
-	Key   string `json:"key"`
-	Value any    `json:"value"`
+	Key   string `json:"key" yaml:"key"`
+	Value any    `json:"value" yaml:"value"`
 }
 
 type RegexMatcher struct {
@@ -58,7 +71,8 @@ func (rp RegexPath) Compile() *regexp.Regexp {
 	return compiled
 }
 
-func (m Mock) Validate() (bool, []string) {
+// Validate checks the fields of the mock, followed by the additional checks, e.g. against an API contract.
+func (m Mock) Validate(checks ...func(Mock) []string) (bool, []string) {
 	val := reflect.ValueOf(m)
 	var validationErrors []string
 	for i := 0; i < val.NumField(); i++ {
@@ -83,6 +97,9 @@ func (m Mock) Validate() (bool, []string) {
 		}
 	}
 	validateMissingData(m, &validationErrors)
+	for _, check := range checks {
+		validationErrors = append(validationErrors, check(m)...)
+	}
 	return len(validationErrors) == 0, validationErrors
 }
 
@@ -91,6 +108,24 @@ func validateMissingData(mock Mock, validationErrors *[]string) {
 	validateHeaderMatchers(mock, validationErrors)
 	validateQueryMatchers(mock, validationErrors)
 	validateBodyMatchers(mock, validationErrors)
+	validateCertMatchers(mock, validationErrors)
+	validateTags(mock, validationErrors)
+	validateNamespace(mock, validationErrors)
+}
+
+func validateNamespace(mock Mock, validationErrors *[]string) {
+	if len(mock.Namespace) != 0 && !IsValidNamespace(mock.Namespace) {
+		*validationErrors = append(*validationErrors, InvalidNamespace)
+	}
+}
+
+func validateTags(mock Mock, validationErrors *[]string) {
+	for i := range mock.Tags {
+		if len(strings.TrimSpace(mock.Tags[i])) == 0 {
+			*validationErrors = append(*validationErrors, InvalidTag)
+			break
+		}
+	}
 }
 
 func validateBodyMatchers(mock Mock, validationErrors *[]string) {
@@ -128,6 +163,16 @@ func validateHeaderMatchers(mock Mock, validationErrors *[]string) {
 	}
 }
 
+func validateCertMatchers(mock Mock, validationErrors *[]string) {
+	for i := range mock.RequestCertMatchers {
+		matcher := mock.RequestCertMatchers[i]
+		if !slices.Contains(CertMatcherKeys, strings.ToLower(matcher.Key)) || len(fmt.Sprint(matcher.Value)) == 0 {
+			*validationErrors = append(*validationErrors, InvalidCertMatcher)
+			break
+		}
+	}
+}
+
 func validatePath(mock Mock, validationErrors *[]string) {
 	if (len(mock.Path) == 0 && len(mock.RegexPath) == 0) || (len(mock.Path) != 0 && len(mock.RegexPath) != 0) {
 		*validationErrors = append(*validationErrors, InvalidPath)
@@ -165,3 +210,46 @@ func (a *Matchers) Scan(value any) error {
 	}
 	return json.Unmarshal(b, &a)
 }
+
+func (a Tags) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *Tags) Scan(value any) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &a)
+}
+
+// NaturalKey identifies a mock independently of its ID. Named mocks are identified by their name,
+// others by method, path and matchers. Both are scoped to the namespace and session.
+func (m Mock) NaturalKey() string {
+	if len(m.Name) != 0 {
+		return fmt.Sprintf("%s|%s|name:%s", m.Namespace, m.SessionID, m.Name)
+	}
+	headerMatchers := slices.Clone(m.RequestHeaderMatchers)
+	for i := range headerMatchers {
+		headerMatchers[i].Key = http.CanonicalHeaderKey(headerMatchers[i].Key)
+	}
+	matchers, _ := json.Marshal([]Matchers{sortMatchers(headerMatchers), sortMatchers(m.RequestQueryMatchers), sortMatchers(m.RequestBodyMatchers), sortMatchers(m.RequestCertMatchers)})
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", m.Namespace, m.SessionID, m.Method, m.Path, m.RegexPath, matchers)
+}
+
+// Equals compares mocks ignoring their IDs.
+func (m Mock) Equals(other Mock) bool {
+	m.ID, other.ID = 0, 0
+	a, errA := json.Marshal(m)
+	b, errB := json.Marshal(other)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+func sortMatchers(matchers Matchers) Matchers {
+	sorted := slices.Clone(matchers)
+	slices.SortStableFunc(sorted, func(a, b Matcher) int { return strings.Compare(a.Key, b.Key) })
+	return sorted
+}
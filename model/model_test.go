@@ -22,9 +22,11 @@ func TestMock_Validate(t *testing.T) {
 		{"Invalid BodyMatcher invalid JsonPath", false, model.InvalidBodyMatcherJSONPath, bodyMatcherInvalidJSONPath},
 		{"Invalid QueryMatcher missing field", false, model.InvalidQueryMatcher, queryMatcherMissingField},
 		{"Invalid HeaderMatcher missing field", false, model.InvalidHeaderMatcher, headerMatcherMissingField},
+		{"Invalid CertMatcher unknown key", false, model.InvalidCertMatcher, certMatcherUnknownKey},
 		{"Missing method", false, model.CanNotBeEmpty, missingMethod},
 		{"Invalid method", false, model.InvalidValue, invalidMethod},
 		{"Invalid status", false, model.InvalidValue, invalidStatus},
+		{"Invalid tag", false, model.InvalidTag, invalidTag},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
@@ -39,6 +41,81 @@ func TestMock_Validate(t *testing.T) {
 	}
 }
 
+func TestMock_Validate_Checks(t *testing.T) {
+	documented := func(mock model.Mock) []string { return nil }
+	undocumented := func(mock model.Mock) []string { return []string{"ResponseStatus - not documented"} }
+	if ok, errors := validSimple.Validate(documented); !ok {
+		t.Errorf("Validate() = %v, want no errors", errors)
+	}
+	ok, errors := validSimple.Validate(documented, undocumented)
+	if ok || !containsError("ResponseStatus - not documented", errors) {
+		t.Errorf("Validate() = %v %v, want contract error", ok, errors)
+	}
+}
+
+func TestMock_NaturalKey(t *testing.T) {
+	reordered := validFull
+	reordered.ID = 7
+	reordered.RequestBodyMatchers = []model.Matcher{{"$.foo", "bar"}, {"$.test", "test"}}
+	reordered.RequestHeaderMatchers = []model.Matcher{{"TEST", "test"}}
+	renamed := validFull
+	renamed.Name = "renamed"
+	unnamed := validFull
+	unnamed.Name = ""
+
+	if validFull.NaturalKey() != reordered.NaturalKey() {
+		t.Errorf("NaturalKey() = %v, want %v", reordered.NaturalKey(), validFull.NaturalKey())
+	}
+	if validFull.NaturalKey() == renamed.NaturalKey() {
+		t.Errorf("NaturalKey() = %v, want different key", renamed.NaturalKey())
+	}
+	if unnamed.NaturalKey() == validSimple.NaturalKey() {
+		t.Errorf("NaturalKey() = %v, want different key", unnamed.NaturalKey())
+	}
+}
+
+func TestMock_MatchesClientCert(t *testing.T) {
+	mock := model.Mock{RequestCertMatchers: []model.Matcher{{"CN", "billing"}, {"san", "billing.local"}, {"issuer", "Mockery CA"}}}
+	cert := &model.ClientCert{CommonName: "billing", SANs: []string{"billing.local", "10.0.0.1"}, Issuer: "Mockery CA"}
+	other := &model.ClientCert{CommonName: "orders", SANs: []string{"billing.local"}, Issuer: "Mockery CA"}
+
+	if !mock.MatchesClientCert(cert) {
+		t.Errorf("MatchesClientCert() = false, want true")
+	}
+	if mock.MatchesClientCert(other) || mock.MatchesClientCert(nil) {
+		t.Errorf("MatchesClientCert() = true, want false")
+	}
+	if !(model.Mock{}).MatchesClientCert(nil) {
+		t.Errorf("MatchesClientCert() without matchers = false, want true")
+	}
+	results := mock.ExplainClientCert(other)
+	if len(results) != 3 || results[0].Matched || results[0].Actual[0] != "orders" || !results[1].Matched {
+		t.Errorf("ExplainClientCert() = %+v", results)
+	}
+}
+
+func TestImportOptions_Synced(t *testing.T) {
+	tests := []struct {
+		name    string
+		options model.ImportOptions
+		source  string
+		want    bool
+	}{
+		{"API mock", model.ImportOptions{}, "", false},
+		{"import directory", model.ImportOptions{}, "billing/foo.json", true},
+		{"below dir", model.ImportOptions{Dir: "billing"}, "billing/foo.json", true},
+		{"outside dir", model.ImportOptions{Dir: "billing"}, "orders/foo.json", false},
+		{"dir prefix", model.ImportOptions{Dir: "bill"}, "billing/foo.json", false},
+		{"imported file", model.ImportOptions{Files: []string{"foo.json"}}, "foo.json", true},
+		{"other file", model.ImportOptions{Files: []string{"foo.json"}}, "bar.json", false},
+	}
+	for _, tt := range tests {
+		if got := tt.options.Synced(tt.source); got != tt.want {
+			t.Errorf("%s: Synced(%q) = %t, want %t", tt.name, tt.source, got, tt.want)
+		}
+	}
+}
+
 func containsError(errStr string, errors []string) bool {
 	if len(errStr) == 0 {
 		return true
@@ -59,11 +136,15 @@ var (
 		ResponseBody:   make(model.JSONB),
 	}
 	validFull = model.Mock{
+		Name:                  "test",
+		Description:           "test mock",
+		Tags:                  model.Tags{"test"},
 		Method:                "POST",
 		RegexPath:             "\\/test\\/\\d+",
 		RequestBodyMatchers:   []model.Matcher{{"$.test", "test"}, {"$.foo", "bar"}},
 		RequestQueryMatchers:  []model.Matcher{{"test", "test"}},
 		RequestHeaderMatchers: []model.Matcher{{"test", "test"}},
+		RequestCertMatchers:   []model.Matcher{{"CN", "test"}},
 		ResponseStatus:        200,
 		ResponseBody:          make(model.JSONB),
 	}
@@ -108,6 +189,13 @@ var (
 		ResponseStatus:        200,
 		ResponseBody:          make(model.JSONB),
 	}
+	certMatcherUnknownKey = model.Mock{
+		Method:              "POST",
+		Path:                "/test",
+		RequestCertMatchers: []model.Matcher{{"subject", "billing"}},
+		ResponseStatus:      200,
+		ResponseBody:        make(model.JSONB),
+	}
 	missingMethod = model.Mock{
 		Path:           "/test",
 		ResponseStatus: 200,
@@ -125,4 +213,11 @@ var (
 		ResponseStatus: 123,
 		ResponseBody:   make(model.JSONB),
 	}
+	invalidTag = model.Mock{
+		Method:         "POST",
+		Path:           "/test",
+		Tags:           model.Tags{"test", " "},
+		ResponseStatus: 200,
+		ResponseBody:   make(model.JSONB),
+	}
 )
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"regexp"
+)
+
+const DefaultNamespaceHeader string = "X-Mockery-Namespace"
+
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+func IsValidNamespace(namespace string) bool {
+	return namespacePattern.MatchString(namespace)
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"slices"
+)
+
+// Selector picks mocks within a single namespace.
+type Selector struct {
+	Namespace string   `json:"namespace,omitempty"`
+	SessionID string   `json:"sessionId,omitempty"`
+	ID        int64    `json:"id,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// IsEmpty reports whether no mock attributes are selected. The namespace and session are not taken into account.
+func (s Selector) IsEmpty() bool {
+	return s.ID == 0 && len(s.Name) == 0 && len(s.Tags) == 0
+}
+
+// Matches reports whether mock belongs to the namespace (and session, if selected), has the selected id and name and carries all selected tags.
+func (s Selector) Matches(mock Mock) bool {
+	if s.Namespace != mock.Namespace {
+		return false
+	}
+	if len(s.SessionID) != 0 && s.SessionID != mock.SessionID {
+		return false
+	}
+	if s.ID != 0 && s.ID != mock.ID {
+		return false
+	}
+	if len(s.Name) != 0 && s.Name != mock.Name {
+		return false
+	}
+	for _, tag := range s.Tags {
+		if !slices.Contains(mock.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	SessionHeader  string = "X-Mockery-Session"
+	UnknownSession        = "Unknown session"
+)
+
+type Session struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// SessionCleanup reports what was removed together with a session.
+type SessionCleanup struct {
+	Mocks          int `json:"mocks"`
+	JournalEntries int `json:"journalEntries"`
+}
@@ -0,0 +1,332 @@
+package openapi
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const (
+	// PathsRegex turns templated paths into regex paths matching any parameter value,
+	// PathsExample fills in parameter examples to get a literal path.
+	PathsRegex   = "regex"
+	PathsExample = "example"
+
+	UnsupportedPaths = "Unsupported paths. Use 'regex' or 'example'."
+)
+
+var (
+	pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+	methods       = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace}
+)
+
+type Options struct {
+	Paths string
+	// File is reported in the import results.
+	File string
+}
+
+func IsValidPaths(paths string) bool {
+	return paths == PathsRegex || paths == PathsExample
+}
+
+// Read generates mocks of every operation response in an OpenAPI 3 document.
+// Error is returned if the document itself can not be read.
+func Read(contents []byte, options Options) ([]model.ImportResult, error) {
+	doc, err := Parse(contents)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	return doc.Mocks(options), nil
+}
+
+// Mocks generates a mock per response (and per named example) of every operation.
+// Only the first mock of an operation is enabled, preferring 2xx responses; the others can be enabled by name.
+func (d Document) Mocks(options Options) []model.ImportResult {
+	options.Paths = cmp.Or(options.Paths, PathsRegex)
+	results := []model.ImportResult{}
+	for _, path := range slices.Sorted(maps.Keys(d.Paths)) {
+		item := d.Paths[path]
+		for _, method := range methods {
+			operation := item.operation(method)
+			if operation == nil {
+				continue
+			}
+			results = append(results, d.operationMocks(method, path, item.Parameters, *operation, options)...)
+		}
+	}
+	return results
+}
+
+func failedResult(file string, method string, path string, err error) model.ImportResult {
+	return model.ImportResult{File: file, Status: model.ImportFailed, Errors: []string{fmt.Sprintf("%s %s: %s", method, path, err.Error())}}
+}
+
+func (p PathItem) operation(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodPut:
+		return p.Put
+	case http.MethodPost:
+		return p.Post
+	case http.MethodDelete:
+		return p.Delete
+	case http.MethodOptions:
+		return p.Options
+	case http.MethodHead:
+		return p.Head
+	case http.MethodPatch:
+		return p.Patch
+	case http.MethodTrace:
+		return p.Trace
+	}
+	return nil
+}
+
+func (d Document) operationMocks(method string, path string, shared []Parameter, operation Operation, options Options) []model.ImportResult {
+	base := model.Mock{
+		Name:        cmp.Or(operation.OperationID, method+" "+path),
+		Description: cmp.Or(operation.Summary, operation.Description),
+		Tags:        operation.Tags,
+		Method:      method,
+	}
+
+	parameters := map[string]Parameter{}
+	for _, parameter := range append(slices.Clone(shared), operation.Parameters...) {
+		resolved, err := d.parameter(parameter)
+		if err != nil {
+			return []model.ImportResult{failedResult(options.File, method, path, err)}
+		}
+		parameters[resolved.In+":"+resolved.Name] = resolved
+	}
+	for _, key := range slices.Sorted(maps.Keys(parameters)) {
+		parameter := parameters[key]
+		value, declared := d.declaredValue(parameter)
+		if !parameter.Required || !declared {
+			continue
+		}
+		switch parameter.In {
+		case "query":
+			base.RequestQueryMatchers = append(base.RequestQueryMatchers, model.Matcher{Key: parameter.Name, Value: fmt.Sprint(value)})
+		case "header":
+			base.RequestHeaderMatchers = append(base.RequestHeaderMatchers, model.Matcher{Key: parameter.Name, Value: fmt.Sprint(value)})
+		}
+	}
+
+	fullPath := d.BasePath() + path
+	if !pathParameter.MatchString(fullPath) {
+		base.Path = fullPath
+	} else if options.Paths == PathsExample {
+		base.Path, base.RegexPath = d.examplePath(fullPath, slices.Collect(maps.Values(parameters)))
+	} else {
+		base.RegexPath = regexPath(fullPath, `[^/]+`)
+	}
+
+	var results []model.ImportResult
+	enabled := false
+	for _, code := range responseCodes(operation.Responses) {
+		response, err := d.response(operation.Responses[code])
+		if err == nil {
+			var bodies []responseBody
+			bodies, err = d.responseBodies(response)
+			for _, body := range bodies {
+				mock := base
+				mock.Name = fmt.Sprintf("%s-%s", base.Name, code)
+				if len(body.name) != 0 {
+					mock.Name += "-" + body.name
+				}
+				mock.ResponseStatus = statusOf(code)
+				mock.ResponseBody = body.value
+				mock.Disabled = enabled
+				if valid, errs := mock.Validate(); !valid {
+					results = append(results, model.ImportResult{File: options.File, Status: model.ImportFailed, Errors: errs})
+					continue
+				}
+				enabled = true
+				results = append(results, model.ImportResult{File: options.File, Status: model.ImportOK, Mock: &mock})
+			}
+		}
+		if err != nil {
+			results = append(results, failedResult(options.File, method, path+" "+code, err))
+		}
+	}
+	return results
+}
+
+// examplePath fills in the path parameters with their declared values. If a parameter declares none, a regex path
+// matching any value of it is returned instead.
+func (d Document) examplePath(path string, parameters []Parameter) (string, string) {
+	var literal, regex strings.Builder
+	complete := true
+	last := 0
+	for _, match := range pathParameter.FindAllStringIndex(path, -1) {
+		literal.WriteString(path[last:match[0]])
+		regex.WriteString(regexp.QuoteMeta(path[last:match[0]]))
+		name := strings.Trim(path[match[0]:match[1]], "{}")
+		index := slices.IndexFunc(parameters, func(parameter Parameter) bool { return parameter.In == "path" && parameter.Name == name })
+		var value any
+		declared := false
+		if index >= 0 {
+			value, declared = d.declaredValue(parameters[index])
+		}
+		if declared {
+			literal.WriteString(fmt.Sprint(value))
+			regex.WriteString(regexp.QuoteMeta(fmt.Sprint(value)))
+		} else {
+			complete = false
+			regex.WriteString(`[^/]+`)
+		}
+		last = match[1]
+	}
+	if complete {
+		return literal.String() + path[last:], ""
+	}
+	return "", "^" + regex.String() + regexp.QuoteMeta(path[last:]) + "$"
+}
+
+// regexPath matches path parameters with parameter, the rest of the path is matched literally.
+func regexPath(path string, parameter string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	last := 0
+	for _, match := range pathParameter.FindAllStringIndex(path, -1) {
+		builder.WriteString(regexp.QuoteMeta(path[last:match[0]]))
+		builder.WriteString(parameter)
+		last = match[1]
+	}
+	builder.WriteString(regexp.QuoteMeta(path[last:]))
+	builder.WriteString("$")
+	return builder.String()
+}
+
+// responseCodes orders responses by status, 2xx first. The default response is used only if it is the only one.
+func responseCodes(responses map[string]Response) []string {
+	codes := slices.Collect(maps.Keys(responses))
+	if len(codes) > 1 {
+		codes = slices.DeleteFunc(codes, func(code string) bool { return code == "default" })
+	}
+	slices.SortFunc(codes, func(a, b string) int {
+		aSuccess, bSuccess := strings.HasPrefix(a, "2"), strings.HasPrefix(b, "2")
+		if aSuccess != bSuccess {
+			if aSuccess {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return codes
+}
+
+// statusOf converts response codes, including ranges like 4XX, to a status.
+func statusOf(code string) int {
+	if code == "default" {
+		return http.StatusOK
+	}
+	status, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(code), "XX", "00"))
+	if err != nil {
+		return http.StatusOK
+	}
+	return status
+}
+
+type responseBody struct {
+	name  string
+	value model.JSONB
+}
+
+// responseBodies returns the named examples of the JSON content, or a single example or generated sample.
+func (d Document) responseBodies(response Response) ([]responseBody, error) {
+	content, ok := jsonContent(response.Content)
+	if !ok {
+		return []responseBody{{}}, nil
+	}
+	if len(content.Examples) == 0 {
+		value := content.Example
+		if value == nil {
+			value = d.Sample(content.Schema)
+		}
+		body, err := jsonObject(value)
+		if err != nil {
+			return nil, err
+		}
+		return []responseBody{{value: body}}, nil
+	}
+
+	var bodies []responseBody
+	for _, name := range slices.Sorted(maps.Keys(content.Examples)) {
+		example, err := d.example(content.Examples[name])
+		if err != nil {
+			return nil, err
+		}
+		body, err := jsonObject(example.Value)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, responseBody{name: name, value: body})
+	}
+	return bodies, nil
+}
+
+// jsonContent picks application/json, then any other JSON media type.
+func jsonContent(content map[string]MediaType) (MediaType, bool) {
+	if media, ok := content["application/json"]; ok {
+		return media, true
+	}
+	for _, name := range slices.Sorted(maps.Keys(content)) {
+		if strings.HasSuffix(name, "+json") || strings.HasSuffix(name, "/json") {
+			return content[name], true
+		}
+	}
+	return MediaType{}, false
+}
+
+func jsonObject(value any) (model.JSONB, error) {
+	if value == nil {
+		return nil, nil
+	}
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New(model.UnsupportedResponseBody)
+	}
+	return object, nil
+}
+
+// declaredValue returns the example, const, default or first enum value the document declares for parameter.
+// Unlike parameterSample it does not fall back to placeholders like "string", which no client sends.
+func (d Document) declaredValue(parameter Parameter) (any, bool) {
+	if parameter.Example != nil {
+		return parameter.Example, true
+	}
+	for _, name := range slices.Sorted(maps.Keys(parameter.Examples)) {
+		if example, err := d.example(parameter.Examples[name]); err == nil && example.Value != nil {
+			return example.Value, true
+		}
+	}
+	schema, err := d.schema(parameter.Schema)
+	if err != nil || schema == nil {
+		return nil, false
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example, true
+	case len(schema.Examples) != 0:
+		return schema.Examples[0], true
+	case schema.Const != nil:
+		return schema.Const, true
+	case schema.Default != nil:
+		return schema.Default, true
+	case len(schema.Enum) != 0:
+		return schema.Enum[0], true
+	}
+	return nil, false
+}
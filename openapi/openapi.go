@@ -0,0 +1,206 @@
+// Package openapi
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	UnsupportedVersion = "Only OpenAPI 3 is supported."
+	UnresolvedRef      = "Unresolved reference"
+)
+
+var ErrInvalidDocument = errors.New("Invalid OpenAPI document")
+
+// Document is the part of an OpenAPI 3 document needed to generate mocks. JSON documents are read as YAML.
+type Document struct {
+	OpenAPI    string              `yaml:"openapi"`
+	Servers    []Server            `yaml:"servers"`
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components Components          `yaml:"components"`
+}
+
+type Server struct {
+	URL       string                    `yaml:"url"`
+	Variables map[string]ServerVariable `yaml:"variables"`
+}
+
+type ServerVariable struct {
+	Default string `yaml:"default"`
+}
+
+type Components struct {
+	Schemas       map[string]*Schema     `yaml:"schemas"`
+	Parameters    map[string]Parameter   `yaml:"parameters"`
+	Responses     map[string]Response    `yaml:"responses"`
+	Examples      map[string]Example     `yaml:"examples"`
+	RequestBodies map[string]RequestBody `yaml:"requestBodies"`
+}
+
+type PathItem struct {
+	Parameters []Parameter `yaml:"parameters"`
+	Get        *Operation  `yaml:"get"`
+	Put        *Operation  `yaml:"put"`
+	Post       *Operation  `yaml:"post"`
+	Delete     *Operation  `yaml:"delete"`
+	Options    *Operation  `yaml:"options"`
+	Head       *Operation  `yaml:"head"`
+	Patch      *Operation  `yaml:"patch"`
+	Trace      *Operation  `yaml:"trace"`
+}
+
+type Operation struct {
+	OperationID string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Tags        []string            `yaml:"tags"`
+	Parameters  []Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody        `yaml:"requestBody"`
+	Responses   map[string]Response `yaml:"responses"`
+}
+
+type RequestBody struct {
+	Ref      string               `yaml:"$ref"`
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+type Parameter struct {
+	Ref      string             `yaml:"$ref"`
+	Name     string             `yaml:"name"`
+	In       string             `yaml:"in"`
+	Required bool               `yaml:"required"`
+	Schema   *Schema            `yaml:"schema"`
+	Example  any                `yaml:"example"`
+	Examples map[string]Example `yaml:"examples"`
+}
+
+type Response struct {
+	Ref         string               `yaml:"$ref"`
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema   *Schema            `yaml:"schema"`
+	Example  any                `yaml:"example"`
+	Examples map[string]Example `yaml:"examples"`
+}
+
+type Example struct {
+	Ref     string `yaml:"$ref"`
+	Summary string `yaml:"summary"`
+	Value   any    `yaml:"value"`
+}
+
+// Schema describes a value. Type is a string in OpenAPI 3.0 and may be a list in 3.1.
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       any                `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Nullable   bool               `yaml:"nullable"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Required   []string           `yaml:"required"`
+	Items      *Schema            `yaml:"items"`
+	AllOf      []*Schema          `yaml:"allOf"`
+	OneOf      []*Schema          `yaml:"oneOf"`
+	AnyOf      []*Schema          `yaml:"anyOf"`
+	Enum       []any              `yaml:"enum"`
+	Default    any                `yaml:"default"`
+	Example    any                `yaml:"example"`
+	Examples   []any              `yaml:"examples"`
+	Const      any                `yaml:"const"`
+	Minimum    *float64           `yaml:"minimum"`
+	Maximum    *float64           `yaml:"maximum"`
+	MinLength  *int               `yaml:"minLength"`
+	MaxLength  *int               `yaml:"maxLength"`
+	Pattern    string             `yaml:"pattern"`
+}
+
+// Parse reads an OpenAPI 3 document in JSON or YAML format.
+func Parse(contents []byte) (Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return doc, fmt.Errorf("%w. %s", ErrInvalidDocument, err.Error())
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return doc, fmt.Errorf("%w. %s", ErrInvalidDocument, UnsupportedVersion)
+	}
+	return doc, nil
+}
+
+// BasePath is the path of the first server url with variables replaced by their defaults.
+func (d Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+	rawURL := d.Servers[0].URL
+	for name, variable := range d.Servers[0].Variables {
+		rawURL = strings.ReplaceAll(rawURL, "{"+name+"}", variable.Default)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(parsed.Path, "/")
+}
+
+func (d Document) parameter(parameter Parameter) (Parameter, error) {
+	if len(parameter.Ref) == 0 {
+		return parameter, nil
+	}
+	resolved, ok := d.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+	if !ok {
+		return parameter, fmt.Errorf("%s [%s]", UnresolvedRef, parameter.Ref)
+	}
+	return d.parameter(resolved)
+}
+
+func (d Document) response(response Response) (Response, error) {
+	if len(response.Ref) == 0 {
+		return response, nil
+	}
+	resolved, ok := d.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	if !ok {
+		return response, fmt.Errorf("%s [%s]", UnresolvedRef, response.Ref)
+	}
+	return d.response(resolved)
+}
+
+func (d Document) requestBody(body *RequestBody) (*RequestBody, error) {
+	if body == nil || len(body.Ref) == 0 {
+		return body, nil
+	}
+	resolved, ok := d.Components.RequestBodies[strings.TrimPrefix(body.Ref, "#/components/requestBodies/")]
+	if !ok {
+		return body, fmt.Errorf("%s [%s]", UnresolvedRef, body.Ref)
+	}
+	return d.requestBody(&resolved)
+}
+
+func (d Document) example(example Example) (Example, error) {
+	if len(example.Ref) == 0 {
+		return example, nil
+	}
+	resolved, ok := d.Components.Examples[strings.TrimPrefix(example.Ref, "#/components/examples/")]
+	if !ok {
+		return example, fmt.Errorf("%s [%s]", UnresolvedRef, example.Ref)
+	}
+	return d.example(resolved)
+}
+
+func (d Document) schema(schema *Schema) (*Schema, error) {
+	if schema == nil || len(schema.Ref) == 0 {
+		return schema, nil
+	}
+	resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	if !ok {
+		return schema, fmt.Errorf("%s [%s]", UnresolvedRef, schema.Ref)
+	}
+	return d.schema(resolved)
+}
@@ -0,0 +1,273 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const petstore = `
+openapi: 3.0.3
+servers:
+  - url: https://{host}/{version}
+    variables:
+      host: { default: api.example.com }
+      version: { default: v1 }
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - { name: limit, in: query, required: true, schema: { type: integer, minimum: 10 } }
+        - $ref: '#/components/parameters/ApiKey'
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  items: { type: array, items: { $ref: '#/components/schemas/Pet' } }
+  /pets/{petId}:
+    parameters:
+      - { name: petId, in: path, required: true, schema: { type: string }, example: rex }
+    get:
+      operationId: getPet
+      summary: Get a pet
+      responses:
+        '404':
+          $ref: '#/components/responses/NotFound'
+        '200':
+          description: Pet
+          content:
+            application/json:
+              examples:
+                cat: { value: { id: 1, name: Tom } }
+                dog: { $ref: '#/components/examples/Dog' }
+    delete:
+      responses:
+        '204': { description: Deleted }
+        '400':
+          description: Names
+          content:
+            application/json:
+              example: [a, b]
+components:
+  parameters:
+    ApiKey: { name: X-Api-Key, in: header, required: true, schema: { type: string, enum: [secret] } }
+  responses:
+    NotFound:
+      description: Not found
+      content:
+        application/problem+json:
+          schema: { type: object, properties: { title: { type: string, default: Not Found } } }
+  examples:
+    Dog: { value: { id: 2, name: Rex } }
+  schemas:
+    Pet:
+      allOf:
+        - type: object
+          properties: { id: { type: integer, format: int64 }, born: { type: string, format: date } }
+        - type: object
+          properties: { name: { type: string }, parent: { $ref: '#/components/schemas/Pet' } }
+`
+
+func TestRead(t *testing.T) {
+	results, err := Read([]byte(petstore), Options{File: "petstore.yaml"})
+	assert.NoError(t, err)
+
+	mocks := map[string]model.Mock{}
+	var failed []model.ImportResult
+	for _, result := range results {
+		assert.Equal(t, "petstore.yaml", result.File)
+		if result.Status == model.ImportOK {
+			mocks[result.Mock.Name] = *result.Mock
+		} else {
+			failed = append(failed, result)
+		}
+	}
+	assert.Len(t, mocks, 5)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, []string{"DELETE /pets/{petId} 400: " + model.UnsupportedResponseBody}, failed[0].Errors)
+
+	list := mocks["listPets-200"]
+	assert.Equal(t, "/v1/pets", list.Path)
+	assert.Equal(t, model.Tags{"pets"}, list.Tags)
+	assert.Empty(t, list.RequestQueryMatchers, "limit declares no value")
+	assert.Equal(t, model.Matchers{{Key: "X-Api-Key", Value: "secret"}}, list.RequestHeaderMatchers)
+	pet := list.ResponseBody["items"].([]any)[0].(map[string]any)
+	assert.Equal(t, "2024-01-01", pet["born"])
+	assert.Equal(t, "string", pet["name"])
+	assert.Contains(t, pet, "parent")
+
+	cat, dog, notFound := mocks["getPet-200-cat"], mocks["getPet-200-dog"], mocks["getPet-404"]
+	assert.Equal(t, `^/v1/pets/[^/]+$`, cat.RegexPath)
+	assert.Equal(t, "Get a pet", cat.Description)
+	assert.False(t, cat.Disabled)
+	assert.True(t, dog.Disabled)
+	assert.Equal(t, "Rex", dog.ResponseBody["name"])
+	assert.True(t, notFound.Disabled)
+	assert.Equal(t, 404, notFound.ResponseStatus)
+	assert.Equal(t, model.JSONB{"title": "Not Found"}, notFound.ResponseBody)
+
+	deleted := mocks["DELETE /pets/{petId}-204"]
+	assert.Equal(t, 204, deleted.ResponseStatus)
+	assert.Nil(t, deleted.ResponseBody)
+}
+
+func TestRead_ExamplePaths(t *testing.T) {
+	results, err := Read([]byte(petstore), Options{Paths: PathsExample})
+	assert.NoError(t, err)
+	for _, result := range results {
+		if result.Status == model.ImportOK && result.Mock.Name == "getPet-200-cat" {
+			assert.Equal(t, "/v1/pets/rex", result.Mock.Path)
+			assert.Empty(t, result.Mock.RegexPath)
+		}
+	}
+
+	results, err = Read([]byte(`
+openapi: 3.0.3
+info: { title: Orders, version: "1" }
+paths:
+  /users/{user}/orders/{id}:
+    get:
+      parameters:
+        - { name: user, in: path, required: true, schema: { type: string, enum: [john, jane] } }
+        - { name: id, in: path, required: true, schema: { type: integer } }
+        - { name: page, in: query, required: true, schema: { type: integer, default: 1 } }
+        - { name: sort, in: query, required: true, schema: { type: string } }
+      responses:
+        '200': { description: OK }
+`), Options{Paths: PathsExample})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Empty(t, results[0].Mock.Path)
+		assert.Equal(t, `^/users/john/orders/[^/]+$`, results[0].Mock.RegexPath)
+		assert.Equal(t, model.Matchers{{Key: "page", Value: "1"}}, results[0].Mock.RequestQueryMatchers)
+	}
+}
+
+func TestRead_InvalidDocument(t *testing.T) {
+	_, err := Read([]byte(`{"swagger": "2.0"}`), Options{})
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+	_, err = Read([]byte(`openapi: [`), Options{})
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+}
+
+const contract = `
+openapi: 3.1.0
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      parameters:
+        - { name: X-Tenant, in: header, required: true, schema: { type: string, enum: [acme] } }
+        - { name: ids, in: query, schema: { type: array, items: { type: integer } } }
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [item]
+              properties:
+                item: { type: string, minLength: 2 }
+                quantity: { type: integer, minimum: 1 }
+      responses:
+        '201':
+          content:
+            application/json:
+              schema: { $ref: '#/components/schemas/Order' }
+        4XX:
+          description: Error
+  /orders/{id}:
+    get:
+      parameters:
+        - { name: id, in: path, required: true, schema: { type: integer }, example: 7 }
+      responses:
+        '200':
+          content:
+            application/json:
+              schema: { $ref: '#/components/schemas/Order' }
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id]
+      properties:
+        id: { type: integer }
+        note: { type: [string, 'null'] }
+`
+
+func TestValidator_ValidateRequest(t *testing.T) {
+	doc, err := Parse([]byte(contract))
+	assert.NoError(t, err)
+	validator, err := NewValidator(doc)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		tenant string
+		body   string
+		errors []string
+	}{
+		{"valid", http.MethodPost, "/orders?ids=1,2", "acme", `{"item":"book","quantity":2}`, nil},
+		{"missing header and body", http.MethodPost, "/orders", "", "", []string{"header parameter 'X-Tenant': missing", "body: missing"}},
+		{"invalid values", http.MethodPost, "/orders?ids=1,x", "other", `{"item":"b","quantity":0.5}`, []string{
+			"header parameter 'X-Tenant': must be one of [acme]",
+			"query parameter 'ids'[1]: expected integer, got string",
+			"$.item: shorter than 2 characters",
+			"$.quantity: expected integer, got number",
+		}},
+		{"missing property", http.MethodPost, "/orders", "acme", `{"quantity":1}`, []string{"$: missing required property 'item'"}},
+		{"invalid path parameter", http.MethodGet, "/orders/abc", "", "", []string{"path parameter 'id': expected integer, got string"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if len(tt.tenant) != 0 {
+				req.Header.Set("X-Tenant", tt.tenant)
+			}
+			result := validator.ValidateRequest(req, req.URL.Path, []byte(tt.body))
+			assert.NotNil(t, result)
+			assert.Equal(t, len(tt.errors) == 0, result.Valid)
+			assert.Equal(t, tt.errors, result.Errors)
+		})
+	}
+
+	assert.Nil(t, validator.ValidateRequest(httptest.NewRequest(http.MethodGet, "/unknown", nil), "/unknown", nil))
+}
+
+func TestValidator_ValidateMock(t *testing.T) {
+	doc, err := Parse([]byte(contract))
+	assert.NoError(t, err)
+	validator, err := NewValidator(doc)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mock   model.Mock
+		errors []string
+	}{
+		{"valid", model.Mock{Method: "GET", Path: "/orders/1", ResponseStatus: 200, ResponseBody: model.JSONB{"id": 1, "note": nil}}, nil},
+		{"valid regex", model.Mock{Method: "GET", RegexPath: `^/orders/\d+$`, ResponseStatus: 200, ResponseBody: model.JSONB{"id": 1}}, nil},
+		{"status range", model.Mock{Method: "POST", Path: "/orders", ResponseStatus: 409}, nil},
+		{"invalid body", model.Mock{Method: "POST", Path: "/orders", ResponseStatus: 201, ResponseBody: model.JSONB{"id": "1"}}, []string{"ResponseBody - $.id: expected integer, got string"}},
+		{"undocumented status", model.Mock{Method: "GET", Path: "/orders/1", ResponseStatus: 500}, []string{UndocumentedStatus + " for GET /orders/{id}: [500]"}},
+		{"undocumented body", model.Mock{Method: "POST", Path: "/orders", ResponseStatus: 400, ResponseBody: model.JSONB{"error": true}}, []string{UndocumentedBody + " for createOrder: [400]"}},
+		{"not in contract", model.Mock{Method: "GET", Path: "/other", ResponseStatus: 500}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.errors, validator.ValidateMock(tt.mock))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+)
+
+// maxDepth stops sampling of recursive schemas.
+const maxDepth = 8
+
+// samples of string formats, other strings are sampled as "string".
+var samples = map[string]any{
+	"date":      "2024-01-01",
+	"date-time": "2024-01-01T00:00:00Z",
+	"email":     "user@example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"uri":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "127.0.0.1",
+	"ipv6":      "::1",
+}
+
+// Sample generates a value matching schema. Examples, defaults and enums are preferred over generated values.
+func (d Document) Sample(schema *Schema) any {
+	return d.sample(schema, 0)
+}
+
+func (d Document) sample(schema *Schema, depth int) any {
+	schema, err := d.schema(schema)
+	if err != nil || schema == nil || depth > maxDepth {
+		return nil
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case len(schema.Examples) != 0:
+		return schema.Examples[0]
+	case schema.Const != nil:
+		return schema.Const
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) != 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) != 0:
+		merged := map[string]any{}
+		for _, part := range schema.AllOf {
+			if object, ok := d.sample(part, depth+1).(map[string]any); ok {
+				maps.Copy(merged, object)
+			}
+		}
+		return merged
+	case len(schema.OneOf) != 0:
+		return d.sample(schema.OneOf[0], depth+1)
+	case len(schema.AnyOf) != 0:
+		return d.sample(schema.AnyOf[0], depth+1)
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		object := map[string]any{}
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if value := d.sample(schema.Properties[name], depth+1); value != nil {
+				object[name] = value
+			}
+		}
+		return object
+	case "array":
+		if item := d.sample(schema.Items, depth+1); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case "integer":
+		if schema.Minimum != nil {
+			return int64(*schema.Minimum)
+		}
+		return 0
+	case "number":
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		if sample, ok := samples[schema.Format]; ok {
+			return sample
+		}
+		return "string"
+	}
+	return nil
+}
+
+// schemaType returns the first non-null type, object if the schema has properties and no type.
+func schemaType(schema *Schema) string {
+	switch value := schema.Type.(type) {
+	case string:
+		return value
+	case []any:
+		for _, item := range value {
+			if name, ok := item.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if len(schema.Properties) != 0 {
+		return "object"
+	}
+	return ""
+}
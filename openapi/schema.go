@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Check validates a JSON value (as decoded by encoding/json) against schema.
+// Errors are prefixed with the JSON path of the invalid value, starting at at.
+func (d Document) Check(schema *Schema, value any, at string) []string {
+	return d.check(schema, value, at, 0)
+}
+
+func (d Document) check(schema *Schema, value any, at string, depth int) []string {
+	schema, err := d.schema(schema)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %s", at, err.Error())}
+	}
+	if schema == nil || depth > maxDepth {
+		return nil
+	}
+
+	var errs []string
+	for _, part := range schema.AllOf {
+		errs = append(errs, d.check(part, value, at, depth+1)...)
+	}
+	for _, alternatives := range [][]*Schema{schema.OneOf, schema.AnyOf} {
+		if len(alternatives) != 0 && !slices.ContainsFunc(alternatives, func(alternative *Schema) bool {
+			return len(d.check(alternative, value, at, depth+1)) == 0
+		}) {
+			errs = append(errs, fmt.Sprintf("%s: does not match any of the alternatives", at))
+		}
+	}
+	if len(schema.Enum) != 0 && !slices.ContainsFunc(schema.Enum, func(option any) bool { return sameJSON(option, value) }) {
+		errs = append(errs, fmt.Sprintf("%s: must be one of %v", at, schema.Enum))
+	}
+
+	if value == nil {
+		if schemaType(schema) != "" && !schema.Nullable && !allowsNull(schema) {
+			errs = append(errs, fmt.Sprintf("%s: expected %s, got null", at, schemaType(schema)))
+		}
+		return errs
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return append(errs, typeError(at, "object", value))
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property '%s'", at, name))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(object)) {
+			if property, ok := schema.Properties[name]; ok {
+				errs = append(errs, d.check(property, object[name], at+"."+name, depth+1)...)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return append(errs, typeError(at, "array", value))
+		}
+		for i := range array {
+			errs = append(errs, d.check(schema.Items, array[i], fmt.Sprintf("%s[%d]", at, i), depth+1)...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return append(errs, typeError(at, "string", value))
+		}
+		length := utf8.RuneCountInString(text)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			errs = append(errs, fmt.Sprintf("%s: shorter than %d characters", at, *schema.MinLength))
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			errs = append(errs, fmt.Sprintf("%s: longer than %d characters", at, *schema.MaxLength))
+		}
+		if len(schema.Pattern) != 0 {
+			if pattern, err := regexp.Compile(schema.Pattern); err == nil && !pattern.MatchString(text) {
+				errs = append(errs, fmt.Sprintf("%s: does not match pattern '%s'", at, schema.Pattern))
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok || (schemaType(schema) == "integer" && number != math.Trunc(number)) {
+			return append(errs, typeError(at, schemaType(schema), value))
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			errs = append(errs, fmt.Sprintf("%s: less than minimum %v", at, *schema.Minimum))
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			errs = append(errs, fmt.Sprintf("%s: greater than maximum %v", at, *schema.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(errs, typeError(at, "boolean", value))
+		}
+	}
+	return errs
+}
+
+// parameterValue converts a parameter string to the JSON type of its schema, so it can be checked.
+// Values that can not be converted are returned unchanged and fail the type check.
+func (d Document) parameterValue(schema *Schema, value string) any {
+	schema, err := d.schema(schema)
+	if err != nil || schema == nil {
+		return value
+	}
+	switch schemaType(schema) {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+	return value
+}
+
+// normalize turns a value into its encoding/json representation, e.g. integers into float64.
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func sameJSON(a any, b any) bool {
+	a, errA := normalize(a)
+	b, errB := normalize(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(a, b)
+}
+
+func allowsNull(schema *Schema) bool {
+	types, ok := schema.Type.([]any)
+	return ok && slices.Contains(types, any("null"))
+}
+
+func typeError(at string, expected string, value any) string {
+	got := "object"
+	switch value.(type) {
+	case string:
+		got = "string"
+	case float64:
+		got = "number"
+	case bool:
+		got = "boolean"
+	case []any:
+		got = "array"
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", at, expected, got)
+}
@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const (
+	UndocumentedStatus = "ResponseStatus - not documented"
+	UndocumentedBody   = "ResponseBody - no JSON body documented"
+)
+
+// Validator checks requests and mocks against the operations of a document.
+// Requests and mocks of paths the document does not describe are not validated.
+type Validator struct {
+	doc    Document
+	routes []route
+}
+
+type route struct {
+	method     string
+	path       string
+	pattern    *regexp.Regexp
+	samplePath string
+	operation  Operation
+	parameters []Parameter
+}
+
+func (r route) name() string {
+	return cmp.Or(r.operation.OperationID, r.method+" "+r.path)
+}
+
+// LoadValidator reads the document from file.
+func LoadValidator(file string) (*Validator, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+	return NewValidator(doc)
+}
+
+func NewValidator(doc Document) (*Validator, error) {
+	validator := &Validator{doc: doc}
+	for path, item := range doc.Paths {
+		fullPath := doc.BasePath() + path
+		for _, method := range methods {
+			operation := item.operation(method)
+			if operation == nil {
+				continue
+			}
+			var parameters []Parameter
+			for _, parameter := range append(slices.Clone(item.Parameters), operation.Parameters...) {
+				resolved, err := doc.parameter(parameter)
+				if err != nil {
+					return nil, fmt.Errorf("%w. %s %s: %s", ErrInvalidDocument, method, path, err.Error())
+				}
+				parameters = slices.DeleteFunc(parameters, func(p Parameter) bool { return p.In == resolved.In && p.Name == resolved.Name })
+				parameters = append(parameters, resolved)
+			}
+			validator.routes = append(validator.routes, route{
+				method:     method,
+				path:       fullPath,
+				pattern:    regexp.MustCompile(regexPath(fullPath, `([^/]+)`)),
+				samplePath: doc.samplePath(fullPath, parameters),
+				operation:  *operation,
+				parameters: parameters,
+			})
+		}
+	}
+	// Literal paths take precedence over templated ones, e.g. /pets/mine over /pets/{id}.
+	slices.SortFunc(validator.routes, func(a, b route) int {
+		return cmp.Or(
+			cmp.Compare(strings.Count(a.path, "{"), strings.Count(b.path, "{")),
+			strings.Compare(a.path, b.path),
+			strings.Compare(a.method, b.method),
+		)
+	})
+	return validator, nil
+}
+
+func (v *Validator) find(method string, path string) (route, bool) {
+	for _, route := range v.routes {
+		if route.method == method && route.pattern.MatchString(path) {
+			return route, true
+		}
+	}
+	return route{}, false
+}
+
+// ValidateRequest checks parameters and body of a request to path. Nil is returned if no operation matches.
+func (v *Validator) ValidateRequest(req *http.Request, path string, body []byte) *model.ContractResult {
+	route, ok := v.find(req.Method, path)
+	if !ok {
+		return nil
+	}
+	var errs []string
+	pathValues := pathValues(route, path)
+	for _, parameter := range route.parameters {
+		var values []string
+		switch parameter.In {
+		case "path":
+			values = []string{pathValues[parameter.Name]}
+		case "query":
+			values = req.URL.Query()[parameter.Name]
+		case "header":
+			values = req.Header.Values(parameter.Name)
+		default:
+			continue
+		}
+		errs = append(errs, v.checkParameter(parameter, values)...)
+	}
+	errs = append(errs, v.checkRequestBody(route.operation.RequestBody, body)...)
+	return &model.ContractResult{Operation: route.name(), Valid: len(errs) == 0, Errors: errs}
+}
+
+func (v *Validator) checkParameter(parameter Parameter, values []string) []string {
+	at := fmt.Sprintf("%s parameter '%s'", parameter.In, parameter.Name)
+	if len(values) == 0 || (len(values) == 1 && len(values[0]) == 0 && parameter.In == "path") {
+		if parameter.Required {
+			return []string{fmt.Sprintf("%s: missing", at)}
+		}
+		return nil
+	}
+	schema, err := v.doc.schema(parameter.Schema)
+	if err != nil || schema == nil {
+		return nil
+	}
+	if schemaType(schema) != "array" {
+		return v.doc.Check(schema, v.doc.parameterValue(schema, values[0]), at)
+	}
+	var items []any
+	for _, value := range values {
+		for item := range strings.SplitSeq(value, ",") {
+			items = append(items, v.doc.parameterValue(schema.Items, item))
+		}
+	}
+	return v.doc.Check(schema, items, at)
+}
+
+func (v *Validator) checkRequestBody(requestBody *RequestBody, body []byte) []string {
+	requestBody, err := v.doc.requestBody(requestBody)
+	if err != nil {
+		return []string{fmt.Sprintf("body: %s", err.Error())}
+	}
+	if requestBody == nil {
+		return nil
+	}
+	if len(body) == 0 {
+		if requestBody.Required {
+			return []string{"body: missing"}
+		}
+		return nil
+	}
+	content, ok := jsonContent(requestBody.Content)
+	if !ok || content.Schema == nil {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("body: invalid JSON. %s", err.Error())}
+	}
+	return v.doc.Check(content.Schema, value, "$")
+}
+
+// ValidateMock checks the response status and body of a mock against the operation it stubs.
+// Regex path mocks are validated against the operations whose sample path they match.
+func (v *Validator) ValidateMock(mock model.Mock) []string {
+	route, ok := v.mockRoute(mock)
+	if !ok {
+		return nil
+	}
+	response, ok := responseFor(route.operation.Responses, mock.ResponseStatus)
+	if !ok {
+		return []string{fmt.Sprintf("%s for %s: [%d]", UndocumentedStatus, route.name(), mock.ResponseStatus)}
+	}
+	response, err := v.doc.response(response)
+	if err != nil {
+		return []string{fmt.Sprintf("ResponseBody - %s", err.Error())}
+	}
+	content, ok := jsonContent(response.Content)
+	if !ok {
+		if len(mock.ResponseBody) != 0 {
+			return []string{fmt.Sprintf("%s for %s: [%d]", UndocumentedBody, route.name(), mock.ResponseStatus)}
+		}
+		return nil
+	}
+	var body any
+	if mock.ResponseBody != nil {
+		if body, err = normalize(mock.ResponseBody); err != nil {
+			return []string{fmt.Sprintf("ResponseBody - %s", err.Error())}
+		}
+	}
+	var errs []string
+	for _, bodyError := range v.doc.Check(content.Schema, body, "$") {
+		errs = append(errs, "ResponseBody - "+bodyError)
+	}
+	return errs
+}
+
+func (v *Validator) mockRoute(mock model.Mock) (route, bool) {
+	if len(mock.Path) != 0 {
+		return v.find(mock.Method, mock.Path)
+	}
+	pattern, err := regexp.Compile(mock.RegexPath)
+	if err != nil {
+		return route{}, false
+	}
+	for _, route := range v.routes {
+		if route.method == mock.Method && (mock.RegexPath == regexPath(route.path, `[^/]+`) || pattern.MatchString(route.samplePath)) {
+			return route, true
+		}
+	}
+	return route{}, false
+}
+
+// responseFor picks the response documented for status, then its range (e.g. 4XX), then the default response.
+func responseFor(responses map[string]Response, status int) (Response, bool) {
+	for _, code := range []string{fmt.Sprint(status), fmt.Sprintf("%dXX", status/100), fmt.Sprintf("%dxx", status/100), "default"} {
+		if response, ok := responses[code]; ok {
+			return response, true
+		}
+	}
+	return Response{}, false
+}
+
+func pathValues(route route, path string) map[string]string {
+	values := map[string]string{}
+	names := pathParameter.FindAllStringSubmatch(route.path, -1)
+	matches := route.pattern.FindStringSubmatch(path)
+	for i := range names {
+		if i+1 < len(matches) {
+			values[names[i][1]], _ = url.PathUnescape(matches[i+1])
+		}
+	}
+	return values
+}
+
+// samplePath fills in the path parameters with their examples.
+func (d Document) samplePath(path string, parameters []Parameter) string {
+	return pathParameter.ReplaceAllStringFunc(path, func(match string) string {
+		name := strings.Trim(match, "{}")
+		for _, parameter := range parameters {
+			if parameter.In == "path" && parameter.Name == name {
+				return fmt.Sprint(d.parameterSample(parameter))
+			}
+		}
+		return name
+	})
+}
+
+// parameterSample is the example of parameter, a sample of its schema or its name.
+func (d Document) parameterSample(parameter Parameter) any {
+	if parameter.Example != nil {
+		return parameter.Example
+	}
+	for _, name := range slices.Sorted(maps.Keys(parameter.Examples)) {
+		if example, err := d.example(parameter.Examples[name]); err == nil && example.Value != nil {
+			return example.Value
+		}
+	}
+	if value := d.Sample(parameter.Schema); value != nil {
+		return value
+	}
+	return parameter.Name
+}
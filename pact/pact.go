@@ -0,0 +1,247 @@
+// Package pact
+package pact
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+)
+
+const (
+	HTTPInteraction        = "Synchronous/HTTP"
+	UnsupportedInteraction = "Unsupported interaction. Only HTTP interactions can be mocked."
+	IgnoredMatchingRules   = "Matching rules are not applied, requests have to match the example values"
+)
+
+var ErrInvalidPact = errors.New("Invalid pact")
+
+// Pact is a consumer contract of the Pact specification v2 to v4.
+type Pact struct {
+	Consumer     Pacticipant   `json:"consumer"`
+	Provider     Pacticipant   `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Pacticipant struct {
+	Name string `json:"name"`
+}
+
+type Interaction struct {
+	// Type is only set by v4 pacts.
+	Type           string          `json:"type"`
+	Description    string          `json:"description"`
+	ProviderState  string          `json:"providerState"`
+	ProviderStates []ProviderState `json:"providerStates"`
+	Request        Request         `json:"request"`
+	Response       Response        `json:"response"`
+}
+
+type ProviderState struct {
+	Name string `json:"name"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Query is a query string (v2) or a map of values (v3, v4).
+	Query         json.RawMessage            `json:"query"`
+	Headers       map[string]any             `json:"headers"`
+	Body          json.RawMessage            `json:"body"`
+	MatchingRules map[string]json.RawMessage `json:"matchingRules"`
+}
+
+type Response struct {
+	Status  int             `json:"status"`
+	Headers map[string]any  `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// body is the v4 body format.
+type body struct {
+	Content     json.RawMessage `json:"content"`
+	ContentType string          `json:"contentType"`
+	Encoded     any             `json:"encoded"`
+}
+
+type pathRule struct {
+	Regex    string `json:"regex"`
+	Matchers []struct {
+		Regex string `json:"regex"`
+	} `json:"matchers"`
+}
+
+func (i Interaction) states() []string {
+	var states []string
+	if len(i.ProviderState) != 0 {
+		states = append(states, i.ProviderState)
+	}
+	for _, state := range i.ProviderStates {
+		states = append(states, state.Name)
+	}
+	return states
+}
+
+func (i Interaction) isHTTP() bool {
+	return len(i.Type) == 0 || i.Type == HTTPInteraction
+}
+
+// Parse reads a pact file.
+func Parse(contents []byte) (Pact, error) {
+	var pact Pact
+	if err := json.Unmarshal(contents, &pact); err != nil {
+		return pact, fmt.Errorf("%w. %s", ErrInvalidPact, err.Error())
+	}
+	if len(pact.Consumer.Name) == 0 || len(pact.Provider.Name) == 0 {
+		return pact, fmt.Errorf("%w. %s", ErrInvalidPact, "Consumer and provider names are required.")
+	}
+	return pact, nil
+}
+
+// Read turns the HTTP interactions of a pact into mocks named by their description.
+// Only the first of the interactions matching the same request is enabled.
+func Read(file string, contents []byte) ([]model.ImportResult, error) {
+	pact, err := Parse(contents)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	results := []model.ImportResult{}
+	enabled := map[string]bool{}
+	for _, interaction := range pact.Interactions {
+		mock, warnings, err := pact.mock(interaction)
+		if err != nil {
+			results = append(results, model.ImportResult{File: file, Status: model.ImportFailed, Errors: []string{fmt.Sprintf("%s: %s", interaction.Description, err.Error())}})
+			continue
+		}
+		if valid, errs := mock.Validate(); !valid {
+			results = append(results, model.ImportResult{File: file, Status: model.ImportFailed, Errors: errs})
+			continue
+		}
+		unnamed := mock
+		unnamed.Name = ""
+		mock.Disabled = enabled[unnamed.NaturalKey()]
+		enabled[unnamed.NaturalKey()] = true
+		results = append(results, model.ImportResult{File: file, Status: model.ImportOK, Mock: &mock, Warnings: warnings})
+	}
+	return results, nil
+}
+
+func (p Pact) mock(interaction Interaction) (model.Mock, []string, error) {
+	if !interaction.isHTTP() {
+		return model.Mock{}, nil, errors.New(UnsupportedInteraction)
+	}
+	request := interaction.Request
+	mock := model.Mock{
+		Name:           interaction.Description,
+		Tags:           model.Tags{p.Consumer.Name, p.Provider.Name},
+		Method:         strings.ToUpper(request.Method),
+		Path:           request.Path,
+		ResponseStatus: interaction.Response.Status,
+	}
+	if states := interaction.states(); len(states) != 0 {
+		mock.Description = "Given " + strings.Join(states, ", ")
+	}
+
+	var warnings []string
+	var ignored []string
+	for _, name := range slices.Sorted(maps.Keys(request.MatchingRules)) {
+		var rule pathRule
+		json.Unmarshal(request.MatchingRules[name], &rule)
+		pattern := rule.Regex
+		if len(rule.Matchers) != 0 {
+			pattern = rule.Matchers[0].Regex
+		}
+		if (name == "path" || name == "$.path") && len(pattern) != 0 {
+			mock.Path, mock.RegexPath = "", "^"+strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")+"$"
+		} else {
+			ignored = append(ignored, name)
+		}
+	}
+	if len(ignored) != 0 {
+		warnings = append(warnings, fmt.Sprintf("%s %v", IgnoredMatchingRules, ignored))
+	}
+
+	query, err := queryOf(request.Query)
+	if err != nil {
+		return model.Mock{}, nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		mock.RequestQueryMatchers = append(mock.RequestQueryMatchers, model.Matcher{Key: name, Value: query.Get(name)})
+	}
+	for _, name := range slices.Sorted(maps.Keys(request.Headers)) {
+		if !util.IsIgnoredHeader(name) {
+			mock.RequestHeaderMatchers = append(mock.RequestHeaderMatchers, model.Matcher{Key: name, Value: headerValue(request.Headers[name])})
+		}
+	}
+	requestBody, err := bodyOf(request.Body, len(interaction.Type) != 0)
+	if err != nil {
+		return model.Mock{}, nil, err
+	}
+	mock.RequestBodyMatchers = util.BodyMatchers(requestBody)
+
+	responseBody, err := bodyOf(interaction.Response.Body, len(interaction.Type) != 0)
+	if err != nil {
+		return model.Mock{}, nil, err
+	}
+	if mock.ResponseBody, err = util.ResponseBody(responseBody); err != nil {
+		return model.Mock{}, nil, err
+	}
+	if mock.ResponseStatus == 0 {
+		mock.ResponseStatus = http.StatusOK
+	}
+	return mock, warnings, nil
+}
+
+func queryOf(raw json.RawMessage) (url.Values, error) {
+	if len(raw) == 0 {
+		return url.Values{}, nil
+	}
+	var rawQuery string
+	if json.Unmarshal(raw, &rawQuery) == nil {
+		return url.ParseQuery(rawQuery)
+	}
+	var query url.Values
+	if err := json.Unmarshal(raw, &query); err != nil {
+		return nil, fmt.Errorf("Invalid query. %s", err.Error())
+	}
+	return query, nil
+}
+
+// headerValue joins headers given as list (v4).
+func headerValue(value any) string {
+	if values, ok := value.([]any); ok {
+		parts := make([]string, len(values))
+		for i := range values {
+			parts[i] = fmt.Sprint(values[i])
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// bodyOf returns the JSON of a body given as value (v2, v3) or as v4 body with content.
+func bodyOf(raw json.RawMessage, isV4 bool) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var v4 body
+	if !isV4 || json.Unmarshal(raw, &v4) != nil || len(v4.Content) == 0 {
+		return raw, nil
+	}
+	var content string
+	if json.Unmarshal(v4.Content, &content) != nil {
+		return v4.Content, nil
+	}
+	if encoded, _ := v4.Encoded.(string); encoded == "base64" || v4.Encoded == true {
+		return base64.StdEncoding.DecodeString(content)
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,135 @@
+package pact
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const pactV3 = `{
+  "consumer": { "name": "web" },
+  "provider": { "name": "orders" },
+  "interactions": [
+    {
+      "description": "get order",
+      "providerStates": [{ "name": "order 1 exists" }],
+      "request": {
+        "method": "GET",
+        "path": "/orders/1",
+        "query": { "expand": ["items"] },
+        "headers": { "Accept": "application/json", "X-Tenant": "acme" },
+        "matchingRules": {
+          "path": { "matchers": [{ "match": "regex", "regex": "/orders/[0-9]+" }] },
+          "header": { "X-Tenant": { "matchers": [{ "match": "type" }] } }
+        }
+      },
+      "response": { "status": 200, "body": { "id": 1, "status": "open" } }
+    },
+    {
+      "description": "get missing order",
+      "providerState": "no orders",
+      "request": { "method": "GET", "path": "/orders/1", "query": { "expand": ["items"] }, "headers": { "X-Tenant": "acme" },
+        "matchingRules": { "path": { "matchers": [{ "match": "regex", "regex": "/orders/[0-9]+" }] } } },
+      "response": { "status": 404 }
+    },
+    {
+      "description": "create order",
+      "request": { "method": "POST", "path": "/orders", "body": { "item": "book", "count": 2 } },
+      "response": { "status": 201, "body": { "id": 2 } }
+    }
+  ]
+}`
+
+const pactV4 = `{
+  "consumer": { "name": "web" },
+  "provider": { "name": "orders" },
+  "interactions": [
+    {
+      "type": "Synchronous/HTTP",
+      "description": "list orders",
+      "request": { "method": "GET", "path": "/orders", "query": { "page": ["2"] }, "headers": { "X-Tenant": ["acme"] } },
+      "response": { "status": 200, "body": { "content": "eyJpdGVtcyI6IFtdfQ==", "contentType": "application/json", "encoded": "base64" } }
+    },
+    {
+      "type": "Synchronous/HTTP",
+      "description": "cancel order",
+      "request": { "method": "POST", "path": "/orders/1/cancel", "body": { "content": { "reason": "late" }, "contentType": "application/json", "encoded": false } },
+      "response": { "status": 200, "body": { "content": { "status": "cancelled" } } }
+    },
+    { "type": "Asynchronous/Messages", "description": "order created" }
+  ]
+}`
+
+func TestRead(t *testing.T) {
+	results, err := Read("web-orders.json", []byte(pactV3))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+	}
+
+	order := results[0].Mock
+	assert.Equal(t, "get order", order.Name)
+	assert.Equal(t, "Given order 1 exists", order.Description)
+	assert.Equal(t, model.Tags{"web", "orders"}, order.Tags)
+	assert.Equal(t, "^/orders/[0-9]+$", order.RegexPath)
+	assert.Equal(t, model.Matchers{{Key: "expand", Value: "items"}}, order.RequestQueryMatchers)
+	assert.Equal(t, model.Matchers{{Key: "X-Tenant", Value: "acme"}}, order.RequestHeaderMatchers)
+	assert.Equal(t, model.JSONB{"id": float64(1), "status": "open"}, order.ResponseBody)
+	assert.Equal(t, []string{IgnoredMatchingRules + " [header]"}, results[0].Warnings)
+	assert.False(t, order.Disabled)
+
+	missing := results[1].Mock
+	assert.Equal(t, "Given no orders", missing.Description)
+	assert.Equal(t, 404, missing.ResponseStatus)
+	assert.True(t, missing.Disabled)
+
+	create := results[2].Mock
+	assert.Equal(t, "/orders", create.Path)
+	assert.ElementsMatch(t, model.Matchers{{Key: "$.item", Value: "book"}, {Key: "$.count", Value: "2"}}, create.RequestBodyMatchers)
+}
+
+func TestRead_V4(t *testing.T) {
+	results, err := Read("web-orders.json", []byte(pactV4))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+	assert.Equal(t, model.Matchers{{Key: "X-Tenant", Value: "acme"}}, results[0].Mock.RequestHeaderMatchers)
+	assert.Equal(t, model.JSONB{"items": []any{}}, results[0].Mock.ResponseBody)
+
+	assert.Equal(t, model.ImportOK, results[1].Status, results[1].Errors)
+	assert.Equal(t, model.Matchers{{Key: "$.reason", Value: "late"}}, results[1].Mock.RequestBodyMatchers)
+	assert.Equal(t, model.JSONB{"status": "cancelled"}, results[1].Mock.ResponseBody)
+
+	assert.Equal(t, model.ImportFailed, results[2].Status)
+	assert.Equal(t, []string{"order created: " + UnsupportedInteraction}, results[2].Errors)
+
+	_, err = Read("web-orders.json", []byte(`{"interactions": []}`))
+	assert.ErrorIs(t, err, ErrInvalidPact)
+}
+
+func TestVerify(t *testing.T) {
+	contract, err := Parse([]byte(pactV4))
+	assert.NoError(t, err)
+	entries := []model.JournalEntry{
+		{Request: model.JournalRequest{Method: "GET", URL: "/orders?page=2", Headers: http.Header{"X-Tenant": {"acme"}}}},
+		{Request: model.JournalRequest{Method: "GET", URL: "/orders?page=2", Headers: http.Header{"X-Tenant": {"acme"}}}},
+		{Request: model.JournalRequest{Method: "POST", URL: "/orders/1/cancel", Body: `{"reason": "early"}`}},
+	}
+
+	report := Verify(contract, entries)
+
+	assert.False(t, report.Verified)
+	assert.Equal(t, 1, report.Exercised)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, []InteractionReport{
+		{Description: "list orders", Requests: 2, Exercised: true},
+		{Description: "cancel order"},
+		{Description: "order created", Error: UnsupportedInteraction},
+	}, report.Interactions)
+}
@@ -0,0 +1,59 @@
+package pact
+
+import (
+	"net/url"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+// Report tells which interactions of a pact the consumer exercised.
+type Report struct {
+	Consumer     string              `json:"consumer"`
+	Provider     string              `json:"provider"`
+	Verified     bool                `json:"verified"`
+	Exercised    int                 `json:"exercised"`
+	Total        int                 `json:"total"`
+	Interactions []InteractionReport `json:"interactions"`
+}
+
+type InteractionReport struct {
+	Description    string   `json:"description"`
+	ProviderStates []string `json:"providerStates,omitempty"`
+	// Requests counts the journal entries matching the interaction request.
+	Requests  int    `json:"requests"`
+	Exercised bool   `json:"exercised"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Verify compares journal entries with the requests of the pact interactions. The pact is verified once
+// every HTTP interaction was requested at least once. Interactions which can not be mocked are reported with an error.
+func Verify(pact Pact, entries []model.JournalEntry) Report {
+	report := Report{Consumer: pact.Consumer.Name, Provider: pact.Provider.Name, Interactions: []InteractionReport{}}
+	for _, interaction := range pact.Interactions {
+		interactionReport := InteractionReport{Description: interaction.Description, ProviderStates: interaction.states()}
+		mock, _, err := pact.mock(interaction)
+		if err != nil {
+			interactionReport.Error = err.Error()
+			report.Interactions = append(report.Interactions, interactionReport)
+			continue
+		}
+		for _, entry := range entries {
+			requestURL, err := url.Parse(entry.Request.URL)
+			if err != nil {
+				continue
+			}
+			if mock.MatchesRoute(entry.Request.Method, requestURL.Path) &&
+				mock.MatchesRequest(requestURL.Query(), entry.Request.Headers, []byte(entry.Request.Body)) {
+				interactionReport.Requests++
+			}
+		}
+		interactionReport.Exercised = interactionReport.Requests != 0
+		report.Total++
+		if interactionReport.Exercised {
+			report.Exercised++
+		}
+		report.Interactions = append(report.Interactions, interactionReport)
+	}
+	report.Verified = report.Exercised == report.Total
+	return report
+}
@@ -0,0 +1,224 @@
+// Package posting
+package posting
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileSuffix marks Posting request files.
+	FileSuffix = ".posting.yaml"
+
+	// EnvFile holds variables of the request files in its folder.
+	EnvFile = ".env"
+
+	AdminRequest = "Admin request. Only 'POST /config' requests are turned into mocks."
+)
+
+var variable = regexp.MustCompile(`\$(\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Za-z_][A-Za-z0-9_]*)`)
+
+// Request is a request saved by Posting.
+type Request struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description"`
+	Method      string  `yaml:"method"`
+	URL         string  `yaml:"url"`
+	Body        *Body   `yaml:"body"`
+	Headers     []Field `yaml:"headers"`
+	Params      []Field `yaml:"params"`
+}
+
+type Body struct {
+	Content     string `yaml:"content"`
+	ContentType string `yaml:"content_type"`
+}
+
+type Field struct {
+	Name    string `yaml:"name"`
+	Value   string `yaml:"value"`
+	Enabled *bool  `yaml:"enabled"`
+}
+
+func (f Field) enabled() bool {
+	return f.Enabled == nil || *f.Enabled
+}
+
+func IsRequestFile(name string) bool {
+	return strings.HasSuffix(name, FileSuffix)
+}
+
+// Read turns a request file into a mock matching the request. The mock responds with 200 and an empty body.
+// Requests creating mocks ('POST /config') are imported as the mock they create.
+// Variables ('$NAME' or '${NAME}') are replaced, unknown variables are kept.
+func Read(file string, contents []byte, variables map[string]string, tags ...string) model.ImportResult {
+	var request Request
+	if err := yaml.Unmarshal(contents, &request); err != nil {
+		return failed(file, fmt.Sprintf("Failed to parse file. %s", err.Error()))
+	}
+	mock, err := request.resolve(variables).Mock()
+	if err != nil {
+		return failed(file, err.Error())
+	}
+	mock.Tags = append(mock.Tags, tags...)
+	if valid, errs := mock.Validate(); !valid {
+		return failed(file, errs...)
+	}
+	return model.ImportResult{File: file, Status: model.ImportOK, Mock: &mock}
+}
+
+// ReadDir reads request files in dir and its subfolders, using the variables of the .env file in dir.
+// Folder names are added to the tags of their mocks. A single request file may be given instead of dir.
+func ReadDir(dir string) ([]model.ImportResult, error) {
+	results := []model.ImportResult{}
+	dir = filepath.Clean(dir)
+	root := dir
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		root = filepath.Dir(dir)
+	}
+	variables, err := ReadEnv(filepath.Join(root, EnvFile))
+	if err != nil {
+		return results, err
+	}
+	err = filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !IsRequestFile(entry.Name()) {
+			return err
+		}
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			results = append(results, failed(file, fmt.Sprintf("Failed to read file. %s", err.Error())))
+			return nil
+		}
+		var tags []string
+		if folder, _ := filepath.Rel(root, filepath.Dir(file)); folder != "." {
+			tags = strings.Split(filepath.ToSlash(folder), "/")
+		}
+		results = append(results, Read(file, contents, variables, tags...))
+		return nil
+	})
+	return results, err
+}
+
+// ReadEnv reads 'NAME=value' lines of an env file. A missing file has no variables.
+func ReadEnv(file string) (map[string]string, error) {
+	variables := map[string]string{}
+	contents, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return variables, nil
+	}
+	if err != nil {
+		return variables, err
+	}
+	for line := range strings.Lines(string(contents)) {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(name, "#") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, "'")
+		}
+		variables[strings.TrimPrefix(strings.TrimSpace(name), "export ")] = value
+	}
+	return variables, nil
+}
+
+func (r Request) resolve(variables map[string]string) Request {
+	replace := func(value string) string {
+		return variable.ReplaceAllStringFunc(value, func(match string) string {
+			if resolved, ok := variables[strings.Trim(match, "${}")]; ok {
+				return resolved
+			}
+			return match
+		})
+	}
+	r.URL = replace(r.URL)
+	if r.Body != nil {
+		r.Body = &Body{Content: replace(r.Body.Content), ContentType: r.Body.ContentType}
+	}
+	r.Headers, r.Params = slices.Clone(r.Headers), slices.Clone(r.Params)
+	for i := range r.Headers {
+		r.Headers[i].Value = replace(r.Headers[i].Value)
+	}
+	for i := range r.Params {
+		r.Params[i].Value = replace(r.Params[i].Value)
+	}
+	return r
+}
+
+func (r Request) Mock() (model.Mock, error) {
+	method := cmp.Or(strings.ToUpper(r.Method), http.MethodGet)
+	path, query := splitURL(r.URL)
+	if path == "/config" || strings.HasPrefix(path, "/config/") {
+		if method != http.MethodPost || path != "/config" || r.Body == nil {
+			return model.Mock{}, errors.New(AdminRequest)
+		}
+		var mock model.Mock
+		if err := json.Unmarshal([]byte(r.Body.Content), &mock); err != nil {
+			return model.Mock{}, fmt.Errorf("Failed to parse mock. %s", err.Error())
+		}
+		mock.Name = cmp.Or(mock.Name, r.Name)
+		return mock, nil
+	}
+
+	mock := model.Mock{
+		Name:           r.Name,
+		Description:    r.Description,
+		Method:         method,
+		ResponseStatus: http.StatusOK,
+	}
+	mock.Path, mock.RegexPath = util.RequestPath(path)
+	for _, param := range r.Params {
+		if param.enabled() {
+			query.Add(param.Name, param.Value)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		mock.RequestQueryMatchers = append(mock.RequestQueryMatchers, model.Matcher{Key: name, Value: query.Get(name)})
+	}
+	for _, header := range r.Headers {
+		if header.enabled() && !util.IsIgnoredHeader(header.Name) {
+			mock.RequestHeaderMatchers = append(mock.RequestHeaderMatchers, model.Matcher{Key: header.Name, Value: header.Value})
+		}
+	}
+	if r.Body != nil {
+		mock.RequestBodyMatchers = util.BodyMatchers([]byte(r.Body.Content))
+	}
+	return mock, nil
+}
+
+// splitURL returns the path and query of a url. Host and variables before the path (e.g. '$BASE_URL') are dropped.
+func splitURL(rawURL string) (string, url.Values) {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		rawURL = rawURL[i+3:]
+	}
+	i := strings.Index(rawURL, "/")
+	if i < 0 {
+		return "/", url.Values{}
+	}
+	path, rawQuery, _ := strings.Cut(rawURL[i:], "?")
+	query, _ := url.ParseQuery(rawQuery)
+	return path, query
+}
+
+func failed(file string, errs ...string) model.ImportResult {
+	return model.ImportResult{File: file, Status: model.ImportFailed, Errors: errs}
+}
@@ -0,0 +1,83 @@
+package posting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const request = `
+name: Find person
+method: POST
+url: $BASE_URL/person/:id/find?verbose=true
+body:
+  content: |-
+    { "name": "${NAME}", "address": { "city": "Warsaw" }, "tags": ["a"] }
+  content_type: application/json
+headers:
+  - { name: Content-Type, value: application/json }
+  - { name: X-Client, value: tests }
+  - { name: X-Disabled, value: "1", enabled: false }
+params:
+  - { name: limit, value: "10" }
+`
+
+const configRequest = `
+name: Create mock
+method: POST
+url: http://localhost:8080/config
+body:
+  content: |-
+    { "method": "GET", "path": "/hello", "responseStatus": 200, "responseBody": { "greeting": "hi" } }
+`
+
+func TestRead(t *testing.T) {
+	result := Read("find.posting.yaml", []byte(request), map[string]string{"NAME": "John"}, "people")
+
+	assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+	mock := result.Mock
+	assert.Equal(t, "Find person", mock.Name)
+	assert.Equal(t, "POST", mock.Method)
+	assert.Equal(t, "^/person/[^/]+/find$", mock.RegexPath)
+	assert.Equal(t, model.Tags{"people"}, mock.Tags)
+	assert.Equal(t, model.Matchers{{Key: "limit", Value: "10"}, {Key: "verbose", Value: "true"}}, mock.RequestQueryMatchers)
+	assert.Equal(t, model.Matchers{{Key: "X-Client", Value: "tests"}}, mock.RequestHeaderMatchers)
+	assert.ElementsMatch(t, model.Matchers{{Key: "$.name", Value: "John"}, {Key: "$.address.city", Value: "Warsaw"}, {Key: "$.tags[0]", Value: "a"}}, mock.RequestBodyMatchers)
+	assert.Equal(t, 200, mock.ResponseStatus)
+}
+
+func TestRead_ConfigRequest(t *testing.T) {
+	result := Read("hello.posting.yaml", []byte(configRequest), nil)
+
+	assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+	assert.Equal(t, "Create mock", result.Mock.Name)
+	assert.Equal(t, "/hello", result.Mock.Path)
+	assert.Equal(t, model.JSONB{"greeting": "hi"}, result.Mock.ResponseBody)
+
+	result = Read("list.posting.yaml", []byte("method: GET\nurl: http://localhost:8080/config/list"), nil)
+	assert.Equal(t, model.ImportFailed, result.Status)
+	assert.Equal(t, []string{AdminRequest}, result.Errors)
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "people"), 0o755)
+	os.WriteFile(filepath.Join(dir, EnvFile), []byte("# names\nNAME=\"John\"\nexport BASE_URL='http://localhost'\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "people", "find.posting.yaml"), []byte(request), 0o644)
+	os.WriteFile(filepath.Join(dir, "hello.posting.yaml"), []byte(configRequest), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.yaml"), []byte("name: not a request"), 0o644)
+
+	results, err := ReadDir(dir)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+	}
+	assert.Equal(t, model.Tags{"people"}, results[1].Mock.Tags)
+	assert.Contains(t, results[1].Mock.RequestBodyMatchers, model.Matcher{Key: "$.name", Value: "John"})
+}
@@ -0,0 +1,225 @@
+// Package postman
+package postman
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+)
+
+const UnsupportedCollection = "Unsupported collection. Only Postman v2.1 collections are supported."
+
+var (
+	ErrInvalidCollection = errors.New("Invalid Postman collection")
+
+	variable = regexp.MustCompile(`\{\{([^}]+)\}\}`)
+)
+
+// Collection is a Postman v2.1 collection.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []KeyValue `json:"variable"`
+}
+
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is either a folder of items or a request with its saved example responses.
+type Item struct {
+	Name     string     `json:"name"`
+	Item     []Item     `json:"item"`
+	Request  *Request   `json:"request"`
+	Response []Response `json:"response"`
+}
+
+type Request struct {
+	Method string     `json:"method"`
+	Header []KeyValue `json:"header"`
+	URL    URL        `json:"url"`
+	Body   *Body      `json:"body"`
+}
+
+// UnmarshalJSON accepts the short form of a request, its url.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	var rawURL string
+	if json.Unmarshal(data, &rawURL) == nil {
+		*r = Request{Method: http.MethodGet, URL: URL{Raw: rawURL}}
+		return nil
+	}
+	type request Request
+	return json.Unmarshal(data, (*request)(r))
+}
+
+type URL struct {
+	Raw   string     `json:"raw"`
+	Path  []any      `json:"path"`
+	Query []KeyValue `json:"query"`
+}
+
+// UnmarshalJSON accepts urls given as string.
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var rawURL string
+	if json.Unmarshal(data, &rawURL) == nil {
+		*u = URL{Raw: rawURL}
+		return nil
+	}
+	type plainURL URL
+	return json.Unmarshal(data, (*plainURL)(u))
+}
+
+type Body struct {
+	Mode string `json:"mode"`
+	Raw  string `json:"raw"`
+}
+
+type Response struct {
+	Name            string     `json:"name"`
+	OriginalRequest *Request   `json:"originalRequest"`
+	Code            int        `json:"code"`
+	Header          []KeyValue `json:"header"`
+	Body            string     `json:"body"`
+}
+
+type KeyValue struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Read turns the requests of a collection into mocks, one per saved example response.
+// Requests without examples respond with 200 and an empty body. Folder names are added as tags.
+func Read(file string, contents []byte) ([]model.ImportResult, error) {
+	var collection Collection
+	if err := json.Unmarshal(contents, &collection); err != nil {
+		return []model.ImportResult{}, fmt.Errorf("%w. %s", ErrInvalidCollection, err.Error())
+	}
+	if !strings.Contains(collection.Info.Schema, "v2.1") {
+		return []model.ImportResult{}, fmt.Errorf("%w. %s", ErrInvalidCollection, UnsupportedCollection)
+	}
+	variables := map[string]string{}
+	for _, kv := range collection.Variable {
+		variables[kv.Key] = fmt.Sprint(kv.Value)
+	}
+	return readItems(file, collection.Item, nil, variables), nil
+}
+
+func readItems(file string, items []Item, folders []string, variables map[string]string) []model.ImportResult {
+	results := []model.ImportResult{}
+	for _, item := range items {
+		if item.Request == nil {
+			results = append(results, readItems(file, item.Item, slices.Concat(folders, []string{item.Name}), variables)...)
+			continue
+		}
+		if len(item.Response) == 0 {
+			results = append(results, mockResult(file, item.Request.mock(item.Name, folders, variables), nil))
+			continue
+		}
+		// Only the first of the examples matching the same request is enabled.
+		enabled := map[string]bool{}
+		for _, response := range item.Response {
+			request := cmp.Or(response.OriginalRequest, item.Request)
+			mock := request.mock(item.Name+" - "+response.Name, folders, variables)
+			mock.ResponseStatus = cmp.Or(response.Code, http.StatusOK)
+			body, err := util.ResponseBody([]byte(response.Body))
+			mock.ResponseBody = body
+			unnamed := mock
+			unnamed.Name = ""
+			mock.Disabled = enabled[unnamed.NaturalKey()]
+			enabled[unnamed.NaturalKey()] = true
+			results = append(results, mockResult(file, mock, err))
+		}
+	}
+	return results
+}
+
+func mockResult(file string, mock model.Mock, err error) model.ImportResult {
+	if err != nil {
+		return model.ImportResult{File: file, Status: model.ImportFailed, Errors: []string{fmt.Sprintf("%s: %s", mock.Name, err.Error())}}
+	}
+	if valid, errs := mock.Validate(); !valid {
+		return model.ImportResult{File: file, Status: model.ImportFailed, Errors: errs}
+	}
+	return model.ImportResult{File: file, Status: model.ImportOK, Mock: &mock}
+}
+
+func (r Request) mock(name string, folders []string, variables map[string]string) model.Mock {
+	mock := model.Mock{
+		Name:           name,
+		Tags:           slices.Clone(folders),
+		Method:         cmp.Or(strings.ToUpper(r.Method), http.MethodGet),
+		ResponseStatus: http.StatusOK,
+	}
+	path, query := r.URL.split()
+	mock.Path, mock.RegexPath = util.RequestPath(resolve(path, variables))
+	for _, kv := range query {
+		if !kv.Disabled && kv.Value != nil {
+			mock.RequestQueryMatchers = append(mock.RequestQueryMatchers, model.Matcher{Key: kv.Key, Value: resolve(fmt.Sprint(kv.Value), variables)})
+		}
+	}
+	for _, kv := range r.Header {
+		if !kv.Disabled && !util.IsIgnoredHeader(kv.Key) {
+			mock.RequestHeaderMatchers = append(mock.RequestHeaderMatchers, model.Matcher{Key: kv.Key, Value: resolve(fmt.Sprint(kv.Value), variables)})
+		}
+	}
+	if r.Body != nil && r.Body.Mode == "raw" {
+		mock.RequestBodyMatchers = util.BodyMatchers([]byte(resolve(r.Body.Raw, variables)))
+	}
+	return mock
+}
+
+// split returns the path and query parameters. Path segments that are variables ('{{id}}', ':id') are kept as such.
+func (u URL) split() (string, []KeyValue) {
+	if len(u.Path) != 0 {
+		segments := make([]string, len(u.Path))
+		for i, segment := range u.Path {
+			if object, ok := segment.(map[string]any); ok {
+				segment = object["value"]
+			}
+			segments[i] = fmt.Sprint(segment)
+		}
+		return "/" + strings.Join(segments, "/"), u.Query
+	}
+
+	raw := u.Raw
+	if i := strings.Index(raw, "://"); i >= 0 {
+		raw = raw[i+3:]
+	} else if _, after, ok := strings.Cut(raw, "}}"); ok && strings.HasPrefix(raw, "{{") {
+		raw = after
+	}
+	path := "/"
+	if i := strings.Index(raw, "/"); i >= 0 {
+		path = raw[i:]
+	}
+	path, rawQuery, _ := strings.Cut(path, "?")
+	query := u.Query
+	if len(query) == 0 {
+		values, _ := url.ParseQuery(rawQuery)
+		for _, key := range slices.Sorted(maps.Keys(values)) {
+			query = append(query, KeyValue{Key: key, Value: values.Get(key)})
+		}
+	}
+	return path, query
+}
+
+// resolve replaces collection variables, unknown variables are kept.
+func resolve(value string, variables map[string]string) string {
+	return variable.ReplaceAllStringFunc(value, func(match string) string {
+		if resolved, ok := variables[strings.Trim(match, "{}")]; ok {
+			return resolved
+		}
+		return match
+	})
+}
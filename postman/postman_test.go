@@ -0,0 +1,85 @@
+package postman
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const collection = `{
+  "info": { "name": "Shop", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json" },
+  "variable": [{ "key": "orderId", "value": "42" }],
+  "item": [
+    {
+      "name": "orders",
+      "item": [
+        {
+          "name": "Get order",
+          "request": {
+            "method": "GET",
+            "header": [{ "key": "X-Client", "value": "tests" }, { "key": "Accept", "value": "application/json" }],
+            "url": { "raw": "{{baseUrl}}/orders/{{orderId}}?expand=items", "path": ["orders", "{{orderId}}"], "query": [{ "key": "expand", "value": "items" }] }
+          },
+          "response": [
+            { "name": "Found", "code": 200, "body": "{\"id\": 42, \"status\": \"open\"}" },
+            { "name": "Missing", "code": 404, "body": "{\"error\": \"not found\"}" },
+            {
+              "name": "Other", "code": 404, "body": "",
+              "originalRequest": { "method": "GET", "url": "{{baseUrl}}/orders/7" }
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "name": "Create order",
+      "request": {
+        "method": "post",
+        "url": "https://shop.example.com/orders",
+        "body": { "mode": "raw", "raw": "{\"item\": \"book\", \"count\": 2}" }
+      }
+    }
+  ]
+}`
+
+func TestRead(t *testing.T) {
+	results, err := Read("shop.json", []byte(collection))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	for _, result := range results {
+		assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+	}
+
+	found := results[0].Mock
+	assert.Equal(t, "Get order - Found", found.Name)
+	assert.Equal(t, model.Tags{"orders"}, found.Tags)
+	assert.Equal(t, "GET", found.Method)
+	assert.Equal(t, "/orders/42", found.Path)
+	assert.Equal(t, model.Matchers{{Key: "expand", Value: "items"}}, found.RequestQueryMatchers)
+	assert.Equal(t, model.Matchers{{Key: "X-Client", Value: "tests"}}, found.RequestHeaderMatchers)
+	assert.Equal(t, 200, found.ResponseStatus)
+	assert.Equal(t, model.JSONB{"id": float64(42), "status": "open"}, found.ResponseBody)
+	assert.False(t, found.Disabled)
+
+	assert.Equal(t, 404, results[1].Mock.ResponseStatus)
+	assert.True(t, results[1].Mock.Disabled)
+	assert.Equal(t, "/orders/7", results[2].Mock.Path)
+	assert.False(t, results[2].Mock.Disabled)
+
+	create := results[3].Mock
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "/orders", create.Path)
+	assert.Empty(t, create.Tags)
+	assert.ElementsMatch(t, model.Matchers{{Key: "$.item", Value: "book"}, {Key: "$.count", Value: "2"}}, create.RequestBodyMatchers)
+}
+
+func TestRead_InvalidCollection(t *testing.T) {
+	_, err := Read("shop.json", []byte(`{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"}}`))
+	assert.ErrorIs(t, err, ErrInvalidCollection)
+
+	_, err = Read("shop.json", []byte(`not json`))
+	assert.ErrorIs(t, err, ErrInvalidCollection)
+}
@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rromanowicz/mockery/context"
+	"github.com/rromanowicz/mockery/model"
+)
+
+const NoMockOnRoute = "No mock with the method and path of the request."
+
+// handleConfigMatch runs a described request through mock matching without answering it, so counters and
+// the journal are left untouched. The request is resolved to a namespace and session like a real one,
+// falling back to the namespace and session of the admin request.
+func handleConfigMatch(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var described model.JournalRequest
+		defer req.Body.Close()
+		if err := json.NewDecoder(req.Body).Decode(&described); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		target, err := http.NewRequest(cmp.Or(strings.ToUpper(described.Method), http.MethodGet), described.URL, strings.NewReader(described.Body))
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		if described.Headers != nil {
+			target.Header = described.Headers
+		}
+		namespace, path, ok := resolveNamespace(ctx.Config.Namespaces, target)
+		if !ok {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(model.InvalidNamespace))
+			return
+		}
+
+		report := model.MatchReport{
+			Namespace:  cmp.Or(namespace, namespaceOf(req)),
+			SessionID:  cmp.Or(sessionOf(target), sessionOf(req)),
+			Method:     target.Method,
+			Path:       path,
+			Candidates: []model.MatchCandidate{},
+		}
+		body := []byte(described.Body)
+		if ctx.Contract != nil {
+			report.Contract = ctx.Contract.ValidateRequest(target, path, body)
+		}
+		mocks, err := fetchMocks(ctx, report.Namespace, report.SessionID, target.Method, path)
+		if errors.Is(err, errNoRoute) {
+			report.Message = NoMockOnRoute
+			writeJSON(rw, http.StatusOK, report)
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		for _, mock := range mocks {
+			report.Candidates = append(report.Candidates, model.MatchCandidate{
+				Mock:     mock,
+				Matched:  mock.MatchesRequest(target.URL.Query(), target.Header, body) && mock.MatchesClientCert(described.ClientCert),
+				Matchers: append(mock.ExplainRequest(target.URL.Query(), target.Header, body), mock.ExplainClientCert(described.ClientCert)...),
+			})
+		}
+		if mock, err := filterMocks(mocks, target, described.ClientCert); err != nil {
+			report.Message = err.Error()
+		} else {
+			report.Mock = &mock
+		}
+		writeJSON(rw, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,53 @@
+package routing
+
+import (
+	stdcontext "context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+type namespaceKey struct{}
+
+// NamespaceMiddleware resolves the namespace of the request and strips the namespace path prefix,
+// so that both mock and admin routes see the path without it.
+func NamespaceMiddleware(config model.Namespaces) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			namespace, path, ok := resolveNamespace(config, req)
+			if !ok {
+				rw.WriteHeader(http.StatusBadRequest)
+				rw.Write([]byte(model.InvalidNamespace))
+				return
+			}
+			if path != req.URL.Path {
+				req.URL.Path = path
+				req.URL.RawPath = ""
+			}
+			next.ServeHTTP(rw, req.WithContext(stdcontext.WithValue(req.Context(), namespaceKey{}, namespace)))
+		})
+	}
+}
+
+func resolveNamespace(config model.Namespaces, req *http.Request) (string, string, bool) {
+	path := req.URL.Path
+	if len(config.PathPrefix) != 0 && strings.HasPrefix(path, config.PathPrefix+"/") {
+		namespace, rest, _ := strings.Cut(strings.TrimPrefix(path, config.PathPrefix+"/"), "/")
+		return namespace, "/" + rest, model.IsValidNamespace(namespace)
+	}
+	if namespace := req.Header.Get(config.Header); len(namespace) != 0 {
+		return namespace, path, model.IsValidNamespace(namespace)
+	}
+	host, _, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		host = req.Host
+	}
+	return config.Hosts[host], path, true
+}
+
+func namespaceOf(req *http.Request) string {
+	namespace, _ := req.Context().Value(namespaceKey{}).(string)
+	return namespace
+}
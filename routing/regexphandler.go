@@ -11,7 +11,13 @@ type route struct {
 }
 
 type RegexpHandler struct {
-	routes []*route
+	routes      []*route
+	middlewares []func(http.Handler) http.Handler
+}
+
+// Use registers a middleware wrapping all routes. Middlewares run in registration order.
+func (h *RegexpHandler) Use(middleware func(http.Handler) http.Handler) {
+	h.middlewares = append(h.middlewares, middleware)
 }
 
 func (h *RegexpHandler) Handler(pattern *regexp.Regexp, handler http.Handler) {
@@ -23,6 +29,14 @@ func (h *RegexpHandler) HandleFunc(pattern *regexp.Regexp, handler func(http.Res
 }
 
 func (h *RegexpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var handler http.Handler = http.HandlerFunc(h.route)
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		handler = h.middlewares[i](handler)
+	}
+	handler.ServeHTTP(w, r)
+}
+
+func (h *RegexpHandler) route(w http.ResponseWriter, r *http.Request) {
 	for _, route := range h.routes {
 		if route.pattern.MatchString(r.URL.Path) {
 			route.handler.ServeHTTP(w, r)
@@ -2,37 +2,88 @@
 package routing
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/rromanowicz/mockery/context"
+	"github.com/rromanowicz/mockery/lint"
 	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/snippet"
+	"github.com/rromanowicz/mockery/util"
+)
 
-	"github.com/theory/jsonpath"
+const (
+	MissingSelector = "Missing selector. Provide 'id', 'name' or 'tag' query parameter."
+	MissingUpload   = "Missing upload. Provide mock files or archives as 'file' form fields."
 )
 
+// errNoRoute is returned by fetchMocks when no mock has the method and path of the request.
+var errNoRoute = errors.New("{}")
+
+type countResponse struct {
+	Count int `json:"count"`
+}
+
 func RegisterRoutes(ctx context.Context, handler *RegexpHandler) {
 	regHealth, _ := regexp.Compile("/health")
 	regHelp, _ := regexp.Compile("/help")
 	regConfigList, _ := regexp.Compile("/config/list")
+	regConfigSnippets, _ := regexp.Compile("/config/snippets")
+	regConfigLint, _ := regexp.Compile("/config/lint")
+	regConfigMatch, _ := regexp.Compile("/config/match")
+	regConfigSettings, _ := regexp.Compile("/config/settings")
+	regConfigUpload, _ := regexp.Compile("/config/upload")
+	regConfigDownload, _ := regexp.Compile("/config/download")
 	regConfigImport, _ := regexp.Compile("/config/import")
+	regConfigOpenAPI, _ := regexp.Compile("/config/openapi")
+	regConfigPostman, _ := regexp.Compile("/config/postman")
+	regConfigPosting, _ := regexp.Compile("/config/posting")
+	regConfigHAR, _ := regexp.Compile("/config/har")
+	regConfigWireMock, _ := regexp.Compile("/config/wiremock")
+	regConfigPactVerify, _ := regexp.Compile("/config/pact/verify")
+	regConfigPact, _ := regexp.Compile("/config/pact")
 	regConfigExport, _ := regexp.Compile("/config/export")
+	regConfigEnable, _ := regexp.Compile("/config/enable")
+	regConfigDisable, _ := regexp.Compile("/config/disable")
+	regConfigReset, _ := regexp.Compile("/config/reset")
+	regConfigSession, _ := regexp.Compile("/config/session")
+	regConfigJournal, _ := regexp.Compile("/config/journal")
 	regConfig, _ := regexp.Compile("/config.*")
 	reg, _ := regexp.Compile("/.*")
+	handler.Use(NamespaceMiddleware(ctx.Config.Namespaces))
 	handler.HandleFunc(regHealth, handleHealth)
 	handler.HandleFunc(regHelp, handleHelp)
 	handler.HandleFunc(regConfigList, handleConfigList(ctx))
+	handler.HandleFunc(regConfigSnippets, handleConfigSnippets(ctx))
+	handler.HandleFunc(regConfigLint, handleConfigLint(ctx))
+	handler.HandleFunc(regConfigMatch, handleConfigMatch(ctx))
+	handler.HandleFunc(regConfigSettings, handleConfigSettings(ctx))
+	handler.HandleFunc(regConfigUpload, handleConfigUpload(ctx))
+	handler.HandleFunc(regConfigDownload, handleConfigDownload(ctx))
 	handler.HandleFunc(regConfigImport, handleConfigImport(ctx))
+	handler.HandleFunc(regConfigOpenAPI, handleConfigOpenAPI(ctx))
+	handler.HandleFunc(regConfigPostman, handleConfigPostman(ctx))
+	handler.HandleFunc(regConfigPosting, handleConfigPosting(ctx))
+	handler.HandleFunc(regConfigHAR, handleConfigHAR(ctx))
+	handler.HandleFunc(regConfigWireMock, handleConfigWireMock(ctx))
+	handler.HandleFunc(regConfigPactVerify, handleConfigPactVerify(ctx))
+	handler.HandleFunc(regConfigPact, handleConfigPact(ctx))
 	handler.HandleFunc(regConfigExport, handleConfigExport(ctx))
+	handler.HandleFunc(regConfigEnable, handleConfigEnable(ctx, true))
+	handler.HandleFunc(regConfigDisable, handleConfigEnable(ctx, false))
+	handler.HandleFunc(regConfigReset, handleConfigReset(ctx))
+	handler.HandleFunc(regConfigSession, handleConfigSession(ctx))
+	handler.HandleFunc(regConfigJournal, handleConfigJournal(ctx))
 	handler.HandleFunc(regConfig, handleConfig(ctx))
 	handler.HandleFunc(reg, handleAll(ctx))
 }
@@ -55,12 +106,12 @@ func handleConfig(ctx context.Context) func(rw http.ResponseWriter, req *http.Re
 	return func(rw http.ResponseWriter, req *http.Request) {
 		switch req.Method {
 		case "GET":
-			mocks, err := ctx.MockService.Get(req.Method, req.URL.Path)
+			mocks, err := ctx.MockService.Get(namespaceOf(req), sessionOf(req), req.Method, req.URL.Path)
 			if err != nil {
 				rw.WriteHeader(http.StatusNotFound)
 				rw.Write([]byte(err.Error()))
 			} else {
-				mock, err := filterMocks(mocks, req)
+				mock, err := filterMocks(mocks, req, clientCertOf(req))
 				if err != nil {
 					rw.WriteHeader(http.StatusNotFound)
 					rw.Write([]byte(err.Error()))
@@ -80,7 +131,19 @@ func handleConfig(ctx context.Context) func(rw http.ResponseWriter, req *http.Re
 				rw.Write([]byte(err.Error()))
 				return
 			}
-			ok, errors := requestMock.Validate()
+			// Created mocks get a new id, so a request can not overwrite a mock of another namespace or session.
+			requestMock.ID, requestMock.Source = 0, ""
+			requestMock.Namespace = namespaceOf(req)
+			if sessionID := sessionOf(req); len(sessionID) != 0 {
+				session, ok := ctx.Sessions.Get(sessionID)
+				if !ok || session.Namespace != requestMock.Namespace {
+					rw.WriteHeader(http.StatusBadRequest)
+					rw.Write([]byte(model.UnknownSession))
+					return
+				}
+				requestMock.SessionID = sessionID
+			}
+			ok, errors := ctx.MockService.Validate(requestMock)
 			if !ok {
 				rw.WriteHeader(http.StatusBadRequest)
 				errorsJSON, _ := json.Marshal(errors)
@@ -98,22 +161,84 @@ func handleConfig(ctx context.Context) func(rw http.ResponseWriter, req *http.Re
 				rw.Write([]byte(jsonBody))
 			}
 		case "DELETE":
-			var id int64
-			id, _ = strconv.ParseInt(req.URL.Query().Get("id"), 10, 64)
-			err := ctx.MockService.Delete(id)
-			if err != nil {
-				rw.WriteHeader(http.StatusNotFound)
-				rw.Write([]byte(err.Error()))
-			} else {
-				rw.WriteHeader(http.StatusOK)
+			selector := selectorFromRequest(req)
+			if selector.IsEmpty() {
+				rw.WriteHeader(http.StatusBadRequest)
+				rw.Write([]byte(MissingSelector))
+				return
+			}
+			if len(selector.Name) == 0 && len(selector.Tags) == 0 {
+				err := ctx.MockService.Delete(selector.Namespace, selector.ID)
+				if err != nil {
+					rw.WriteHeader(http.StatusNotFound)
+					rw.Write([]byte(err.Error()))
+				} else {
+					rw.WriteHeader(http.StatusOK)
+				}
+				return
+			}
+			count, err := ctx.MockService.DeleteAll(selector)
+			writeCount(rw, count, err)
+		}
+	}
+}
+
+func handleConfigEnable(ctx context.Context, enabled bool) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		selector := selectorFromRequest(req)
+		if selector.IsEmpty() {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(MissingSelector))
+			return
+		}
+		count, err := ctx.MockService.SetEnabled(selector, enabled)
+		writeCount(rw, count, err)
+	}
+}
+
+func writeCount(rw http.ResponseWriter, count int, err error) {
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	resp, _ := json.Marshal(countResponse{Count: count})
+	rw.Write(resp)
+}
+
+func handleConfigReset(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodDelete {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		count, err := ctx.MockService.Reset(namespaceOf(req))
+		writeCount(rw, count, err)
+	}
+}
+
+// selectorFromRequest reads the 'id', 'name' and 'tag' query parameters within the request namespace.
+// Tags may be repeated or comma separated.
+func selectorFromRequest(req *http.Request) model.Selector {
+	query := req.URL.Query()
+	selector := model.Selector{Namespace: namespaceOf(req)}
+	selector.ID, _ = strconv.ParseInt(query.Get("id"), 10, 64)
+	selector.Name = query.Get("name")
+	for _, value := range query["tag"] {
+		for tag := range strings.SplitSeq(value, ",") {
+			if len(tag) != 0 {
+				selector.Tags = append(selector.Tags, tag)
 			}
 		}
 	}
+	return selector
 }
 
 func handleConfigList(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
 	return func(rw http.ResponseWriter, req *http.Request) {
-		mocks, err := ctx.MockService.List()
+		mocks, err := ctx.MockService.List(selectorFromRequest(req))
 		if err != nil {
 			rw.WriteHeader(http.StatusNotFound)
 			rw.Write([]byte(err.Error()))
@@ -126,25 +251,115 @@ func handleConfigList(ctx context.Context) func(rw http.ResponseWriter, req *htt
 	}
 }
 
+// handleConfigSnippets renders requests matching the selected mocks, as JSON or as 'curl' / 'go' text.
+func handleConfigSnippets(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		format := req.URL.Query().Get("format")
+		if len(format) != 0 && format != snippet.FormatCurl && format != snippet.FormatGo {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(model.UnsupportedFormat))
+			return
+		}
+		mocks, err := ctx.MockService.List(selectorFromRequest(req))
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		options := snippet.Options{BaseURL: "http://" + req.Host, NamespaceHeader: ctx.Config.Namespaces.Header}
+		snippets := []snippet.Snippet{}
+		for _, mock := range mocks {
+			snippets = append(snippets, snippet.For(mock, options))
+		}
+		if len(format) == 0 {
+			writeJSON(rw, http.StatusOK, snippets)
+			return
+		}
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rw.WriteHeader(http.StatusOK)
+		rw.Write([]byte(snippet.Text(snippets, format)))
+	}
+}
+
+// handleConfigLint reports duplicate, shadowed and unreachable mocks of the request namespace.
+func handleConfigLint(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		mocks, err := ctx.MockService.List(model.Selector{Namespace: namespaceOf(req)})
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeJSON(rw, http.StatusOK, lint.Mocks(mocks))
+	}
+}
+
+// handleConfigSettings shows the effective config of the server, with secrets redacted.
+func handleConfigSettings(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(rw, http.StatusOK, ctx.Config.Redacted())
+	}
+}
+
 func handleConfigImport(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
 	return func(rw http.ResponseWriter, req *http.Request) {
-		files, err := ctx.MockService.Import()
+		options, err := importOptionsOf(req)
 		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		results, err := ctx.MockService.Import(req.URL.Query().Get("dir"), options)
+		if errors.Is(err, util.ErrInvalidDirectory) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+		} else if err != nil {
 			rw.WriteHeader(http.StatusInternalServerError)
 			rw.Write([]byte(err.Error()))
 		} else {
 			rw.Header().Set("Content-Type", "application/json")
 			rw.WriteHeader(http.StatusOK)
-			resp, _ := json.Marshal(files)
+			resp, _ := json.Marshal(results)
 			rw.Write(resp)
 		}
 	}
 }
 
+// importOptionsOf reads the 'folders', 'mode' and 'dryRun' query parameters.
+func importOptionsOf(req *http.Request) (model.ImportOptions, error) {
+	query := req.URL.Query()
+	options := model.ImportOptions{Namespace: namespaceOf(req), Folders: query.Get("folders"), Mode: query.Get("mode")}
+	options.DryRun, _ = strconv.ParseBool(query.Get("dryRun"))
+	if len(options.Mode) != 0 && !model.IsValidImportMode(options.Mode) {
+		return options, errors.New(model.UnsupportedImportMode)
+	}
+	if len(options.Folders) != 0 && !model.IsValidImportFolders(options.Folders) {
+		return options, errors.New(model.UnsupportedImportFolders)
+	}
+	return options, nil
+}
+
 func handleConfigExport(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
 	return func(rw http.ResponseWriter, req *http.Request) {
-		files, err := ctx.MockService.Export()
-		if err != nil {
+		format := req.URL.Query().Get("format")
+		if len(format) != 0 && format != model.FormatJSON && format != model.FormatYAML {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(model.UnsupportedFormat))
+			return
+		}
+		files, err := ctx.MockService.Export(selectorFromRequest(req), req.URL.Query().Get("dir"), format)
+		if errors.Is(err, util.ErrInvalidDirectory) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+		} else if err != nil {
 			rw.WriteHeader(http.StatusInternalServerError)
 			rw.Write([]byte(err.Error()))
 		} else {
@@ -158,58 +373,96 @@ func handleConfigExport(ctx context.Context) func(rw http.ResponseWriter, req *h
 
 func handleAll(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
 	return func(rw http.ResponseWriter, req *http.Request) {
-		mocks, err := fetchMocks(ctx, req.Method, req.URL.Path)
-		rw.Header().Set("Content-Type", "application/json")
+		requestBody, err := io.ReadAll(req.Body)
 		if err != nil {
-			log.Println(err.Error())
-			rw.WriteHeader(http.StatusInternalServerError)
-			rw.Write([]byte(err.Error()))
-			return
+			log.Printf("Failed to read request body. %s", err.Error())
 		}
-		mock, err := filterMocks(mocks, req)
-		if err != nil {
-			rw.WriteHeader(http.StatusTeapot)
-			rw.Write([]byte(err.Error()))
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+
+		var mock model.Mock
+		var status int
+		var response []byte
+		var contract *model.ContractResult
+		if ctx.Contract != nil {
+			contract = ctx.Contract.ValidateRequest(req, req.URL.Path, requestBody)
+		}
+		if contract != nil && !contract.Valid {
+			status = http.StatusBadRequest
+			response, _ = json.Marshal(contract)
+		} else if mocks, err := fetchMocks(ctx, namespaceOf(req), sessionOf(req), req.Method, req.URL.Path); err != nil {
+			log.Println(err.Error())
+			status, response = http.StatusInternalServerError, []byte(err.Error())
+		} else if mock, err = filterMocks(mocks, req, clientCertOf(req)); err != nil {
+			status, response = http.StatusTeapot, []byte(err.Error())
 		} else {
-			rw.WriteHeader(mock.ResponseStatus)
-			response, _ := json.Marshal(mock.ResponseBody)
-			rw.Write(response)
+			status = mock.ResponseStatus
+			response, _ = json.Marshal(mock.ResponseBody)
 		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		rw.Write(response)
+
+		ctx.Journal.Record(model.JournalEntry{
+			Namespace: namespaceOf(req),
+			SessionID: sessionOf(req),
+			MockID:    mock.ID,
+			Request: model.JournalRequest{
+				Method:     req.Method,
+				URL:        req.URL.String(),
+				Headers:    req.Header,
+				Body:       string(requestBody),
+				ClientCert: clientCertOf(req),
+			},
+			Response: model.JournalResponse{Status: status, Body: string(response)},
+			Contract: contract,
+		})
 	}
 }
 
-func fetchMocks(ctx context.Context, method string, path string) ([]model.Mock, error) {
-	var mocks []model.Mock
-	var err error
-	mocks, err = ctx.MockService.Get(method, path)
+// fetchMocks returns literal and regex path candidates together, so a more specific
+// regex mock is not hidden by a literal fallback.
+func fetchMocks(ctx context.Context, namespace string, sessionID string, method string, path string) ([]model.Mock, error) {
+	mocks, err := ctx.MockService.Get(namespace, sessionID, method, path)
 	if err != nil {
 		return []model.Mock{}, err
 	}
 
-	if len(mocks) == 0 {
-		regexMatchers, err := ctx.MockService.GetRegexpMatchers(method)
-		if err != nil {
-			return []model.Mock{}, err
-		}
-		var ids []int64
-		for i := range regexMatchers {
-			if regexMatchers[i].RegexPath.Compile().MatchString(path) {
-				ids = append(ids, regexMatchers[i].ID)
-			}
-		}
-		if len(ids) == 0 {
-			return []model.Mock{}, fmt.Errorf("{}")
+	regexMatchers, err := ctx.MockService.GetRegexpMatchers(namespace, sessionID, method)
+	if err != nil {
+		return []model.Mock{}, err
+	}
+	var ids []int64
+	for i := range regexMatchers {
+		if regexMatchers[i].RegexPath.Compile().MatchString(path) &&
+			!slices.ContainsFunc(mocks, func(mock model.Mock) bool { return mock.ID == regexMatchers[i].ID }) {
+			ids = append(ids, regexMatchers[i].ID)
 		}
-		mocks, err = ctx.MockService.GetByIds(ids)
+	}
+	if len(ids) != 0 {
+		regexMocks, err := ctx.MockService.GetByIds(namespace, sessionID, ids)
 		if err != nil {
 			return []model.Mock{}, err
 		}
+		mocks = append(mocks, regexMocks...)
+	}
+	if len(mocks) == 0 {
+		return []model.Mock{}, errNoRoute
 	}
 
+	slices.SortStableFunc(mocks, model.MatchOrder)
 	return mocks, nil
 }
 
-func filterMocks(mocks []model.Mock, req *http.Request) (model.Mock, error) {
+// clientCertOf returns the identity of the verified client certificate of an HTTPS request, if any.
+func clientCertOf(req *http.Request) *model.ClientCert {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return model.ClientCertOf(req.TLS.PeerCertificates[0])
+}
+
+func filterMocks(mocks []model.Mock, req *http.Request, cert *model.ClientCert) (model.Mock, error) {
 	if len(mocks) == 0 {
 		return model.Mock{}, errors.New("not found")
 	}
@@ -223,9 +476,7 @@ func filterMocks(mocks []model.Mock, req *http.Request) (model.Mock, error) {
 
 	for i := range mocks {
 		mock := &mocks[i]
-		if isMatchingRequestBody(mock.RequestBodyMatchers, requestBody) &&
-			isMatchingRequestQuery(mock.RequestQueryMatchers, req.URL.Query()) &&
-			isMatchingRequestHeader(mock.RequestHeaderMatchers, &req.Header) {
+		if mock.MatchesRequest(req.URL.Query(), req.Header, requestBody) && mock.MatchesClientCert(cert) {
 			matchedMocks = append(matchedMocks, mock)
 		}
 	}
@@ -243,72 +494,3 @@ func filterMocks(mocks []model.Mock, req *http.Request) (model.Mock, error) {
 
 	return *matchedMocks[0], nil
 }
-
-func isMatchingRequestQuery(queryMatchers model.Matchers, requestQueryParams url.Values) bool {
-	if len(queryMatchers) == 0 {
-		return true
-	}
-	if len(requestQueryParams) == 0 {
-		return false
-	}
-
-	for _, matcher := range queryMatchers {
-		input := requestQueryParams.Get(matcher.Key)
-		if len(input) == 0 || fmt.Sprintf("%v", matcher.Value) != input {
-			return false
-		}
-	}
-	return true
-}
-
-func isMatchingRequestBody(bodyMatchers model.Matchers, requestBody []byte) bool {
-	if len(bodyMatchers) == 0 {
-		return true
-	}
-	if len(requestBody) == 0 {
-		return false
-	}
-
-	for _, matcher := range bodyMatchers {
-		if !isPathMatching(matcher, &requestBody) {
-			return false
-		}
-	}
-	return true
-}
-
-func isMatchingRequestHeader(headerMatchers model.Matchers, requestHeaders *http.Header) bool {
-	if len(headerMatchers) == 0 {
-		return true
-	}
-	if len(*requestHeaders) == 0 {
-		return false
-	}
-
-	for _, matcher := range headerMatchers {
-		headervalue := requestHeaders.Get(matcher.Key)
-		if len(headervalue) == 0 || matcher.Value != headervalue {
-			return false
-		}
-	}
-	return true
-}
-
-func isPathMatching(matcher model.Matcher, string *[]byte) bool {
-	var value any
-	if err := json.Unmarshal(*string, &value); err != nil {
-		log.Printf("Failed to marshal request body. %s", err.Error())
-	}
-	path, err := jsonpath.Parse(matcher.Key)
-	if err != nil {
-		log.Printf("Failed to parse JsonPath. %s", err.Error())
-	}
-
-	nodes := path.Select(value)
-	for _, node := range nodes {
-		if matcher.Value == fmt.Sprintf("%v", node) {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,92 @@
+package routing
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rromanowicz/mockery/context"
+	"github.com/rromanowicz/mockery/har"
+	"github.com/rromanowicz/mockery/model"
+)
+
+func handleConfigSession(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case "GET":
+			writeJSON(rw, http.StatusOK, ctx.Sessions.List(namespaceOf(req)))
+		case "POST":
+			session, err := ctx.Sessions.Create(namespaceOf(req))
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				rw.Write([]byte(err.Error()))
+				return
+			}
+			writeJSON(rw, http.StatusCreated, session)
+		case "DELETE":
+			id := req.URL.Query().Get("id")
+			if len(id) == 0 {
+				id = sessionOf(req)
+			}
+			session, ok := ctx.Sessions.Get(id)
+			if !ok || session.Namespace != namespaceOf(req) {
+				rw.WriteHeader(http.StatusNotFound)
+				rw.Write([]byte(model.UnknownSession))
+				return
+			}
+			cleanup, err := ctx.Sessions.Delete(id)
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				rw.Write([]byte(err.Error()))
+				return
+			}
+			writeJSON(rw, http.StatusOK, cleanup)
+		default:
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleConfigJournal(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		filter := journalFilterOf(req)
+		switch req.Method {
+		case "GET":
+			if req.URL.Query().Get("format") == "har" {
+				rw.Header().Set("Content-Disposition", `attachment; filename="journal.har"`)
+				writeJSON(rw, http.StatusOK, har.FromJournal(ctx.Journal.List(filter), "http://"+req.Host))
+				return
+			}
+			writeJSON(rw, http.StatusOK, ctx.Journal.List(filter))
+		case "DELETE":
+			writeCount(rw, ctx.Journal.Clear(filter), nil)
+		default:
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// journalFilterOf scopes the journal to the request namespace and the session given by
+// the 'session' query parameter or the session header.
+func journalFilterOf(req *http.Request) model.JournalFilter {
+	filter := model.JournalFilter{Namespace: namespaceOf(req), SessionID: req.URL.Query().Get("session")}
+	if len(filter.SessionID) == 0 {
+		filter.SessionID = sessionOf(req)
+	}
+	return filter
+}
+
+func sessionOf(req *http.Request) string {
+	return req.Header.Get(model.SessionHeader)
+}
+
+func writeJSON(rw http.ResponseWriter, status int, body any) {
+	resp, err := json.Marshal(body)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	rw.Write(resp)
+}
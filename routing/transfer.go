@@ -0,0 +1,393 @@
+package routing
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rromanowicz/mockery/context"
+	"github.com/rromanowicz/mockery/har"
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/openapi"
+	"github.com/rromanowicz/mockery/pact"
+	"github.com/rromanowicz/mockery/posting"
+	"github.com/rromanowicz/mockery/postman"
+	"github.com/rromanowicz/mockery/util"
+	"github.com/rromanowicz/mockery/wiremock"
+)
+
+// uploadNames maps the content type of a raw upload to a file name when 'filename' is not provided.
+var uploadNames = map[string]string{
+	"application/json":   "upload.json",
+	"application/yaml":   "upload.yaml",
+	"application/x-yaml": "upload.yaml",
+	"text/yaml":          "upload.yaml",
+	"application/zip":    "upload.zip",
+	"application/gzip":   "upload.tar.gz",
+	"application/x-gzip": "upload.tar.gz",
+}
+
+func handleConfigUpload(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		options, err := importOptionsOf(req)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		req.Body = http.MaxBytesReader(rw, req.Body, util.MaxUploadSize)
+		files, err := uploadedFiles(req)
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		results, err := ctx.MockService.Upload(files, options)
+		if errors.Is(err, util.ErrInvalidArchive) || errors.Is(err, util.ErrUnsupportedFormat) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeJSON(rw, http.StatusOK, results)
+	}
+}
+
+// uploadStatus is the response status of a failed upload, 413 if it exceeds util.MaxUploadSize.
+func uploadStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// uploadedFiles reads 'file' parts of a multipart form, or the raw request body named by
+// the 'filename' query parameter or its content type.
+func uploadedFiles(req *http.Request) ([]model.MockFile, error) {
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if contentType == "multipart/form-data" {
+		if err := req.ParseMultipartForm(util.MaxUploadSize); err != nil {
+			return nil, err
+		}
+		var files []model.MockFile
+		for _, header := range req.MultipartForm.File["file"] {
+			file, err := header.Open()
+			if err != nil {
+				return nil, err
+			}
+			contents, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, model.MockFile{Name: header.Filename, Contents: contents})
+		}
+		if len(files) == 0 {
+			return nil, errors.New(MissingUpload)
+		}
+		return files, nil
+	}
+
+	name := cmp.Or(req.URL.Query().Get("filename"), uploadNames[contentType])
+	if len(name) == 0 {
+		return nil, fmt.Errorf("%s [%s]", model.UnsupportedFormat, contentType)
+	}
+	contents, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return []model.MockFile{{Name: name, Contents: contents}}, nil
+}
+
+func handleConfigOpenAPI(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		options, err := importOptionsOf(req)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		openapiOptions := openapi.Options{Paths: cmp.Or(req.URL.Query().Get("paths"), openapi.PathsRegex), File: req.URL.Query().Get("filename")}
+		if !openapi.IsValidPaths(openapiOptions.Paths) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(openapi.UnsupportedPaths))
+			return
+		}
+		contents, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, util.MaxUploadSize))
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		results, err := ctx.MockService.ImportOpenAPI(contents, openapiOptions, options)
+		if errors.Is(err, openapi.ErrInvalidDocument) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeJSON(rw, http.StatusOK, results)
+	}
+}
+
+func handleConfigPostman(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		options, err := importOptionsOf(req)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		contents, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, util.MaxUploadSize))
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		results, err := postman.Read(req.URL.Query().Get("filename"), contents)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeImport(rw, ctx, results, options)
+	}
+}
+
+func handleConfigPosting(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		options, err := importOptionsOf(req)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		req.Body = http.MaxBytesReader(rw, req.Body, util.MaxUploadSize)
+		files, err := uploadedFiles(req)
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		var results []model.ImportResult
+		for _, file := range files {
+			results = append(results, posting.Read(file.Name, file.Contents, nil))
+		}
+		writeImport(rw, ctx, results, options)
+	}
+}
+
+func handleConfigHAR(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		options, err := importOptionsOf(req)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		contents, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, util.MaxUploadSize))
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		results, err := har.Read(req.URL.Query().Get("filename"), contents, harOptionsOf(req))
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeImport(rw, ctx, results, options)
+	}
+}
+
+// harOptionsOf reads the matchers to generate: 'body' and 'query' (default true) and a comma separated 'headers' list.
+func harOptionsOf(req *http.Request) har.Options {
+	query := req.URL.Query()
+	options := har.DefaultOptions()
+	if body, err := strconv.ParseBool(query.Get("body")); err == nil {
+		options.Body = body
+	}
+	if matchQuery, err := strconv.ParseBool(query.Get("query")); err == nil {
+		options.Query = matchQuery
+	}
+	for header := range strings.SplitSeq(query.Get("headers"), ",") {
+		if header = strings.TrimSpace(header); len(header) != 0 {
+			options.Headers = append(options.Headers, header)
+		}
+	}
+	return options
+}
+
+// handleConfigWireMock exports mocks as WireMock mappings (GET) or imports uploaded mapping files (POST).
+func handleConfigWireMock(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			mocks, err := ctx.MockService.List(selectorFromRequest(req))
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				rw.Write([]byte(err.Error()))
+				return
+			}
+			writeJSON(rw, http.StatusOK, wiremock.FromMocks(mocks))
+		case http.MethodPost:
+			options, err := importOptionsOf(req)
+			if err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				rw.Write([]byte(err.Error()))
+				return
+			}
+			req.Body = http.MaxBytesReader(rw, req.Body, util.MaxUploadSize)
+			files, err := uploadedFiles(req)
+			if err != nil {
+				rw.WriteHeader(uploadStatus(err))
+				rw.Write([]byte(err.Error()))
+				return
+			}
+			var results []model.ImportResult
+			for _, file := range files {
+				fileResults, err := wiremock.Read(file.Name, file.Contents)
+				if err != nil {
+					rw.WriteHeader(http.StatusBadRequest)
+					rw.Write([]byte(fmt.Sprintf("%s [%s]", err.Error(), file.Name)))
+					return
+				}
+				results = append(results, fileResults...)
+			}
+			writeImport(rw, ctx, results, options)
+		default:
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func handleConfigPact(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		options, err := importOptionsOf(req)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		contents, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, util.MaxUploadSize))
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		results, err := pact.Read(req.URL.Query().Get("filename"), contents)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeImport(rw, ctx, results, options)
+	}
+}
+
+// handleConfigPactVerify reports the interactions of the posted pact exercised by requests in the journal.
+func handleConfigPactVerify(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		contents, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, util.MaxUploadSize))
+		if err != nil {
+			rw.WriteHeader(uploadStatus(err))
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		contract, err := pact.Parse(contents)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		writeJSON(rw, http.StatusOK, pact.Verify(contract, ctx.Journal.List(journalFilterOf(req))))
+	}
+}
+
+// writeImport imports mocks converted from other formats and writes the results.
+func writeImport(rw http.ResponseWriter, ctx context.Context, results []model.ImportResult, options model.ImportOptions) {
+	results, err := ctx.MockService.ImportResults(results, options)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+	writeJSON(rw, http.StatusOK, results)
+}
+
+func handleConfigDownload(ctx context.Context) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		archive := cmp.Or(query.Get("archive"), util.ArchiveZip)
+		format := cmp.Or(query.Get("format"), model.FormatJSON)
+		if (archive != util.ArchiveZip && archive != util.ArchiveTarGz) || (format != model.FormatJSON && format != model.FormatYAML) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(model.UnsupportedFormat))
+			return
+		}
+		mocks, err := ctx.MockService.List(selectorFromRequest(req))
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		var buf bytes.Buffer
+		if err = util.WriteArchive(&buf, archive, format, mocks); err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		contentType := "application/zip"
+		if archive == util.ArchiveTarGz {
+			contentType = "application/gzip"
+		}
+		rw.Header().Set("Content-Type", contentType)
+		rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="mocks.%s"`, archive))
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(buf.Bytes())
+	}
+}
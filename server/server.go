@@ -2,69 +2,169 @@
 package server
 
 import (
+	"cmp"
+	stdcontext "context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
+	"github.com/rromanowicz/mockery/cert"
 	"github.com/rromanowicz/mockery/context"
 	"github.com/rromanowicz/mockery/model"
 	"github.com/rromanowicz/mockery/routing"
 	"gopkg.in/yaml.v3"
 )
 
-const configFilePath string = "mockery.yml"
+const ConfigFilePath string = "mockery.yml"
 
-var ctx context.Context
+// StartMockServer serves config until SIGINT or SIGTERM. A second signal stops the server without draining.
+func StartMockServer(config *model.Config) error {
+	ctx, handler, err := NewHandler(config)
+	if err != nil {
+		return err
+	}
+	listeners, err := Listen(config)
+	if err != nil {
+		ctx.Close()
+		return err
+	}
 
-func StartMockServer(portOverride *int, dbTypeOverride *string) error {
-	config := ReadConfig(portOverride, dbTypeOverride)
-	port, handler := SetupServer(&config)
+	signals, stop := signal.NotifyContext(stdcontext.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	stdcontext.AfterFunc(signals, stop)
+	return Serve(signals, ctx, &http.Server{Handler: handler}, listeners...)
+}
 
-	server := http.Server{
-		Addr:    fmt.Sprintf(":%v", port),
-		Handler: handler,
+// Listen opens the HTTP port of config and, with TLS enabled, the HTTPS port.
+func Listen(config *model.Config) ([]net.Listener, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", config.Port))
+	if err != nil {
+		return nil, err
+	}
+	if !config.TLS.Enabled {
+		return []net.Listener{listener}, nil
+	}
+	tlsConfig, err := cert.Config(config.TLS)
+	if err != nil {
+		listener.Close()
+		return nil, err
 	}
+	tlsListener, err := net.Listen("tcp", fmt.Sprintf(":%v", config.TLS.Port))
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	log.Printf("Serving HTTPS [Port: %v, Cert: %s]", config.TLS.Port, cmp.Or(config.TLS.CertFile, filepath.Join(config.TLS.Dir, cert.CertFile)))
+	return []net.Listener{listener, tls.NewListener(tlsListener, tlsConfig)}, nil
+}
 
+// Serve runs server on listeners until done is cancelled. Open requests are then drained for up to the shutdown
+// timeout, mocks are exported if configured, and ctx is closed.
+func Serve(done stdcontext.Context, ctx context.Context, server *http.Server, listeners ...net.Listener) error {
+	served := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func() {
+			served <- server.Serve(listener)
+		}()
+	}
 	log.Println("Service Started")
-	return server.ListenAndServe()
+
+	select {
+	case err := <-served:
+		server.Close()
+		return errors.Join(err, ctx.Close())
+	case <-done.Done():
+	}
+
+	log.Printf("Shutting down [Timeout: %s]", ctx.Config.ShutdownTimeout)
+	drain, cancel := stdcontext.WithTimeout(stdcontext.Background(), ctx.Config.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(drain); err != nil {
+		log.Printf("Failed to drain requests. %s", err.Error())
+		server.Close()
+	}
+	if ctx.Config.ExportOnShutdown {
+		files, err := ctx.MockService.ExportAll(model.FormatJSON)
+		if err != nil {
+			log.Printf("Failed to export mocks. %s", err.Error())
+		} else {
+			log.Printf("Exported mocks: %d [%s]", len(files), ctx.Config.ExportDir)
+		}
+	}
+	err := ctx.Close()
+	log.Println("Service Stopped")
+	return err
 }
 
+// SetupServer registers the routes of config for callers that only need the handler. It panics on an invalid config.
 func SetupServer(config *model.Config) (int, *routing.RegexpHandler) {
-	var err error
-	ctx, err = context.InitContext(config)
+	_, handler, err := NewHandler(config)
 	if err != nil {
 		panic(err)
 	}
+	return config.Port, handler
+}
 
-	defer ctx.Close()
-
+// NewHandler initializes a context for config and registers the routes on a new handler.
+// It keeps no package state, so several instances can be served side by side.
+func NewHandler(config *model.Config) (context.Context, *routing.RegexpHandler, error) {
+	ctx, err := context.InitContext(config)
+	if err != nil {
+		return ctx, nil, err
+	}
 	handler := &routing.RegexpHandler{}
 	routing.RegisterRoutes(ctx, handler)
+	return ctx, handler, nil
+}
 
-	return config.Port, handler
+// Overrides are flag values applied over the config file and environment. Empty values are ignored.
+// ConnStr is the connection string of the resulting database type.
+type Overrides struct {
+	Port    int
+	DBType  string
+	ConnStr string
 }
 
-func ReadConfig(portOverride *int, dbTypeOverride *string) model.Config {
-	var config model.Config
-	contents, err := os.ReadFile(configFilePath)
-	if err != nil {
-		log.Println("Failed to read or missing config file. Setting default values.")
-	} else {
-		err = yaml.Unmarshal(contents, &config)
-		if err != nil {
-			log.Println("Failed to process config file. Setting default values.")
-		}
+func (o Overrides) apply(config *model.Config) {
+	if len(o.DBType) != 0 {
+		config.DBType = model.Database(o.DBType)
 	}
-	err = config.Validate()
-	if err != nil {
-		panic(err)
+	if o.Port != 0 {
+		config.Port = o.Port
 	}
-	if len(*dbTypeOverride) != 0 {
-		config.DBType = model.Database(*dbTypeOverride)
+	if len(o.ConnStr) != 0 {
+		switch config.DBType {
+		case model.Postgres:
+			config.DBConfig.Postgres.ConnectionString = o.ConnStr
+		default:
+			config.DBConfig.SqLite.ConnectionString = o.ConnStr
+		}
+	}
+}
+
+// ReadConfig layers the config file at path, MOCKERY_* environment variables and overrides over the defaults,
+// and validates the result. A missing default config file is skipped.
+func ReadConfig(path string, overrides Overrides) (model.Config, error) {
+	config := model.Config{DBType: model.InMemory}
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && path == ConfigFilePath {
+		log.Println("Missing config file. Setting default values.")
+	} else if err != nil {
+		return config, err
+	} else if err = yaml.Unmarshal(contents, &config); err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
 	}
-	if *portOverride != 0 {
-		config.Port = *portOverride
+	if err = config.ApplyEnv(os.LookupEnv); err != nil {
+		return config, err
 	}
-	return config
+	overrides.apply(&config)
+	return config, config.Validate()
 }
@@ -2,15 +2,31 @@ package server
 
 import (
 	"bytes"
+	stdcontext "context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/rromanowicz/mockery/cert"
+	"github.com/rromanowicz/mockery/db"
+	"github.com/rromanowicz/mockery/lint"
 	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/routing"
+	"github.com/rromanowicz/mockery/snippet"
+	"github.com/rromanowicz/mockery/util"
 )
 
 func runTestServer() *httptest.Server {
@@ -106,7 +122,1372 @@ func Test_Api_Integration(t *testing.T) {
 	}
 }
 
+func Test_Api_Tags(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	for _, input := range []string{postConfigTaggedBilling, postConfigTaggedOrders} {
+		resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(input))
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	tests := []struct {
+		testName       string
+		expectedStatus int
+		expectedResult string
+		requestMethod  string
+		requestPath    string
+	}{
+		{"GET /config/list?tag=billing", 200, "invoices", "GET", "/config/list?tag=billing"},
+		{"GET /config/list?tag=billing,orders", 200, "[]", "GET", "/config/list?tag=billing,orders"},
+		{"POST /config/disable?tag=billing", 200, `{"count":1}`, "POST", "/config/disable?tag=billing"},
+		{"GET /invoices disabled", 500, "", "GET", "/invoices"},
+		{"POST /config/enable?name=invoices", 200, `{"count":1}`, "POST", "/config/enable?name=invoices"},
+		{"GET /invoices enabled", 200, "", "GET", "/invoices"},
+		{"DELETE /config missing selector", 400, "", "DELETE", "/config"},
+		{"DELETE /config?tag=suite", 200, `{"count":2}`, "DELETE", "/config?tag=suite"},
+		{"GET /config/list empty", 200, "[]", "GET", "/config/list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.requestMethod, fmt.Sprintf("%s%s", ts.URL, tt.requestPath), nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			buf := new(bytes.Buffer)
+			defer resp.Body.Close()
+			_, _ = buf.ReadFrom(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if len(tt.expectedResult) != 0 {
+				assert.Contains(t, buf.String(), tt.expectedResult)
+			}
+		})
+	}
+}
+
+func Test_Api_Namespaces(t *testing.T) {
+	config := model.Config{DBType: "InMemory", Namespaces: model.Namespaces{PathPrefix: "/ns", Hosts: map[string]string{"billing.local": "billing"}}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("%s/config", ts.URL), bytes.NewBufferString(postConfigSimplePath))
+	req.Header.Set(model.DefaultNamespaceHeader, "billing")
+	resp, _ := http.DefaultClient.Do(req)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	tests := []struct {
+		testName       string
+		expectedStatus int
+		expectedResult string
+		requestMethod  string
+		requestPath    string
+		namespace      string
+		host           string
+	}{
+		{"GET /foo default namespace", 500, "", "GET", "/foo", "", ""},
+		{"GET /foo header namespace", 200, `{"bar":{"id":2},"foo":true}`, "GET", "/foo", "billing", ""},
+		{"GET /foo path prefix namespace", 200, `{"bar":{"id":2},"foo":true}`, "GET", "/ns/billing/foo", "", ""},
+		{"GET /foo host namespace", 200, `{"bar":{"id":2},"foo":true}`, "GET", "/foo", "", "billing.local"},
+		{"GET /foo invalid namespace", 400, "", "GET", "/foo", "bill ing", ""},
+		{"GET /config/list default namespace", 200, "[]", "GET", "/config/list", "", ""},
+		{"GET /config/list path prefix namespace", 200, `"namespace":"billing"`, "GET", "/ns/billing/config/list", "", ""},
+		{"DELETE /config/reset default namespace", 200, `{"count":0}`, "DELETE", "/config/reset", "", ""},
+		{"DELETE /config/reset header namespace", 200, `{"count":1}`, "DELETE", "/config/reset", "billing", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.requestMethod, fmt.Sprintf("%s%s", ts.URL, tt.requestPath), nil)
+			if len(tt.namespace) != 0 {
+				req.Header.Set(model.DefaultNamespaceHeader, tt.namespace)
+			}
+			if len(tt.host) != 0 {
+				req.Host = tt.host
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			buf := new(bytes.Buffer)
+			defer resp.Body.Close()
+			_, _ = buf.ReadFrom(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if len(tt.expectedResult) != 0 {
+				assert.Contains(t, buf.String(), tt.expectedResult)
+			}
+		})
+	}
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(`{"namespace": "billing", "method": "GET", "path": "/bar", "responseStatus": 200}`))
+	var mock model.Mock
+	_ = json.NewDecoder(resp.Body).Decode(&mock)
+	resp.Body.Close()
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Empty(t, mock.Namespace)
+
+	list := func(namespace string) []model.Mock {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("%s/config/list", ts.URL), nil)
+		req.Header.Set(model.DefaultNamespaceHeader, namespace)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+		var mocks []model.Mock
+		_ = json.NewDecoder(resp.Body).Decode(&mocks)
+		return mocks
+	}
+	req, _ = http.NewRequest("POST", fmt.Sprintf("%s/config", ts.URL), bytes.NewBufferString(postConfigSimplePath))
+	req.Header.Set(model.DefaultNamespaceHeader, "billing")
+	resp, _ = http.DefaultClient.Do(req)
+	resp.Body.Close()
+	billing := list("billing")
+	if !assert.Len(t, billing, 1) {
+		return
+	}
+	resp, _ = http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(fmt.Sprintf(`{"id": %d, "source": "billing.json", "method": "GET", "path": "/baz", "responseStatus": 200}`, billing[0].ID)))
+	_ = json.NewDecoder(resp.Body).Decode(&mock)
+	resp.Body.Close()
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.NotEqual(t, billing[0].ID, mock.ID)
+	assert.Empty(t, mock.Source)
+	assert.Equal(t, billing, list("billing"))
+}
+
+func Test_Api_Sessions(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(postConfigSimplePath))
+	assert.Equal(t, 201, resp.StatusCode)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/session", ts.URL), "application/json", nil)
+	assert.Equal(t, 201, resp.StatusCode)
+	var session model.Session
+	_ = json.NewDecoder(resp.Body).Decode(&session)
+	resp.Body.Close()
+	assert.NotEmpty(t, session.ID)
+
+	tests := []struct {
+		testName       string
+		expectedStatus int
+		expectedResult string
+		requestMethod  string
+		requestPath    string
+		requestBody    string
+		sessionID      string
+	}{
+		{"POST /config unknown session", 400, model.UnknownSession, "POST", "/config", postConfigSessionPath, "unknown"},
+		{"POST /config session", 201, session.ID, "POST", "/config", postConfigSessionPath, session.ID},
+		{"GET /foo session", 200, `{"bar":{"id":6},"foo":true}`, "GET", "/foo", "", session.ID},
+		{"GET /foo global", 200, `{"bar":{"id":2},"foo":true}`, "GET", "/foo", "", ""},
+		{"GET /foo other session falls back to global", 200, `{"bar":{"id":2},"foo":true}`, "GET", "/foo", "", "other"},
+		{"GET /config/journal session", 200, `"sessionId":"` + session.ID, "GET", "/config/journal", "", session.ID},
+		{"DELETE /config/session", 200, `{"mocks":1,"journalEntries":1}`, "DELETE", "/config/session?id=" + session.ID, "", ""},
+		{"DELETE /config/session unknown", 404, model.UnknownSession, "DELETE", "/config/session?id=" + session.ID, "", ""},
+		{"GET /foo deleted session", 200, `{"bar":{"id":2},"foo":true}`, "GET", "/foo", "", session.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			var reqBody io.Reader = nil
+			if len(tt.requestBody) != 0 {
+				reqBody = bytes.NewBufferString(tt.requestBody)
+			}
+			req, _ := http.NewRequest(tt.requestMethod, fmt.Sprintf("%s%s", ts.URL, tt.requestPath), reqBody)
+			if len(tt.sessionID) != 0 {
+				req.Header.Set(model.SessionHeader, tt.sessionID)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			buf := new(bytes.Buffer)
+			defer resp.Body.Close()
+			_, _ = buf.ReadFrom(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if len(tt.expectedResult) != 0 {
+				assert.Contains(t, buf.String(), tt.expectedResult)
+			}
+		})
+	}
+}
+
+func Test_Api_ImportExportDirs(t *testing.T) {
+	dir := t.TempDir()
+	config := model.Config{DBType: "InMemory", ImportDir: dir, ExportDir: dir}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(postConfigSimplePath))
+	assert.Equal(t, 201, resp.StatusCode)
+
+	tests := []struct {
+		testName       string
+		expectedStatus int
+		expectedResult string
+		requestPath    string
+	}{
+		{"GET /config/export?dir=suite", 200, filepath.Join(dir, "suite"), "/config/export?dir=suite"},
+		{"GET /config/import?dir=suite", 200, `"status":"OK"`, "/config/import?dir=suite"},
+		{"GET /config/export?dir=../suite", 400, model.InvalidDirectory, "/config/export?dir=../suite"},
+		{"GET /config/import?dir=/etc", 400, model.InvalidDirectory, "/config/import?dir=/etc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s%s", ts.URL, tt.requestPath))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			buf := new(bytes.Buffer)
+			defer resp.Body.Close()
+			_, _ = buf.ReadFrom(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Contains(t, buf.String(), tt.expectedResult)
+		})
+	}
+}
+
+func Test_Api_ImportFolders(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"billing/_defaults.json":   `{ "pathPrefix": "/billing", "requestHeaderMatchers": [ { "key": "X-Api-Key", "value": "secret" } ] }`,
+		"billing/invoices.json":    postConfigSimplePath,
+		"billing/v2/invoices.json": postConfigSessionPath,
+		"billing/broken.json":      postConfigMissingPath,
+	}
+	for name, contents := range files {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), os.ModePerm)
+		os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644)
+	}
+	config := model.Config{DBType: "InMemory", ImportDir: dir}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, _ := http.Get(fmt.Sprintf("%s/config/import", ts.URL))
+	assert.Equal(t, 200, resp.StatusCode)
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		if filepath.Base(result.File) == "broken.json" {
+			assert.Equal(t, model.ImportFailed, result.Status)
+			assert.Contains(t, result.Errors, model.InvalidPath)
+		} else {
+			assert.Equal(t, model.ImportOK, result.Status)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("%s/billing/foo", ts.URL), nil)
+	req.Header.Set("X-Api-Key", "secret")
+	resp, _ = http.DefaultClient.Do(req)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/list?tag=billing,v2", ts.URL))
+	var mocks []model.Mock
+	_ = json.NewDecoder(resp.Body).Decode(&mocks)
+	resp.Body.Close()
+	assert.Len(t, mocks, 1)
+	assert.Equal(t, "/billing/foo", mocks[0].Path)
+}
+
+func Test_Api_ImportModes(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte(postConfigSimplePath), 0o644)
+	os.WriteFile(filepath.Join(dir, "bar.json"), []byte(postConfigQueryMatcher), 0o644)
+	config := model.Config{DBType: "InMemory", ImportDir: dir}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	importActions := func(query string) []string {
+		resp, err := http.Get(fmt.Sprintf("%s/config/import?%s", ts.URL, query))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode)
+		var results []model.ImportResult
+		_ = json.NewDecoder(resp.Body).Decode(&results)
+		var actions []string
+		for _, result := range results {
+			actions = append(actions, result.Action)
+		}
+		return actions
+	}
+	countMocks := func() int {
+		resp, err := http.Get(fmt.Sprintf("%s/config/list", ts.URL))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+		var mocks []model.Mock
+		_ = json.NewDecoder(resp.Body).Decode(&mocks)
+		return len(mocks)
+	}
+
+	assert.Equal(t, []string{"created", "created"}, importActions("mode=upsert"))
+	assert.Equal(t, []string{"unchanged", "unchanged"}, importActions("mode=upsert"))
+	assert.Equal(t, 2, countMocks())
+
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte(strings.Replace(postConfigSimplePath, `"id": 2`, `"id": 22`, 1)), 0o644)
+	os.Remove(filepath.Join(dir, "bar.json"))
+	assert.Equal(t, []string{"updated", "deleted"}, importActions("mode=sync&dryRun=true"))
+	assert.Equal(t, 2, countMocks())
+	assert.Equal(t, []string{"updated", "deleted"}, importActions("mode=sync"))
+	assert.Equal(t, 1, countMocks())
+
+	valid, _ := os.ReadFile(filepath.Join(dir, "foo.json"))
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte("{"), 0o644)
+	assert.Equal(t, []string{""}, importActions("mode=sync"))
+	assert.Equal(t, 1, countMocks())
+	os.WriteFile(filepath.Join(dir, "foo.json"), valid, 0o644)
+
+	os.Mkdir(filepath.Join(dir, "billing"), 0o755)
+	os.WriteFile(filepath.Join(dir, "billing", "bar.json"), []byte(postConfigQueryMatcher), 0o644)
+	assert.Equal(t, []string{"created"}, importActions("mode=sync&dir=billing"))
+	assert.Equal(t, 2, countMocks())
+	os.Remove(filepath.Join(dir, "billing", "bar.json"))
+	assert.Equal(t, []string{"deleted"}, importActions("mode=sync&dir=billing"))
+	assert.Equal(t, 1, countMocks())
+
+	resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(postConfigHeaderMatcher))
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, []string{"unchanged"}, importActions("mode=sync"))
+	assert.Equal(t, 2, countMocks())
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("%s/config", ts.URL), bytes.NewBufferString(postConfigQueryMatcher))
+	req.Header.Set(model.DefaultNamespaceHeader, "billing")
+	resp, _ = http.DefaultClient.Do(req)
+	var billing model.Mock
+	_ = json.NewDecoder(resp.Body).Decode(&billing)
+	resp.Body.Close()
+	os.Mkdir(filepath.Join(dir, "create"), 0o755)
+	os.WriteFile(filepath.Join(dir, "create", "foo.json"), []byte(fmt.Sprintf(`{"id": %d, "method": "GET", "path": "/create", "responseStatus": 200}`, billing.ID)), 0o644)
+	assert.Equal(t, []string{"created"}, importActions("mode=create&dir=create"))
+	assert.Equal(t, 3, countMocks())
+	req, _ = http.NewRequest("GET", fmt.Sprintf("%s/config/list", ts.URL), nil)
+	req.Header.Set(model.DefaultNamespaceHeader, "billing")
+	resp, _ = http.DefaultClient.Do(req)
+	var mocks []model.Mock
+	_ = json.NewDecoder(resp.Body).Decode(&mocks)
+	resp.Body.Close()
+	assert.Equal(t, []model.Mock{billing}, mocks)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/import?mode=merge", ts.URL))
+	assert.Equal(t, 400, resp.StatusCode)
+	resp, _ = http.Get(fmt.Sprintf("%s/config/import?folders=tags", ts.URL))
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, model.UnsupportedImportFolders, string(body))
+}
+
+func Test_Api_ImportYAML(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "mocks.yaml"), []byte(yamlMocks), 0o644)
+	os.WriteFile(filepath.Join(dir, "list.yml"), []byte(yamlMockList), 0o644)
+	config := model.Config{DBType: "InMemory", ImportDir: dir, ExportDir: filepath.Join(dir, "export")}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, _ := http.Get(fmt.Sprintf("%s/config/import", ts.URL))
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Len(t, results, 4)
+	for _, result := range results {
+		if result.File == filepath.Join(dir, "mocks.yaml") && result.Line == 15 {
+			assert.Equal(t, model.ImportFailed, result.Status)
+			assert.Equal(t, []string{"line 15: Method - Invalid value: [FETCH]"}, result.Errors)
+		} else {
+			assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+		}
+	}
+
+	resp, _ = http.Get(fmt.Sprintf("%s/yaml/list/2", ts.URL))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, `{"items":[{"id":1},{"id":2}]}`, buf.String())
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/export?format=yaml", ts.URL))
+	var files []string
+	_ = json.NewDecoder(resp.Body).Decode(&files)
+	resp.Body.Close()
+	assert.Len(t, files, 3)
+	for _, file := range files {
+		assert.Equal(t, ".yaml", filepath.Ext(file))
+	}
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/export?format=xml", ts.URL))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_WatchImport(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte(postConfigSimplePath), 0o644)
+	config := model.Config{DBType: "InMemory", ImportDir: dir, AutoImport: true, WatchImport: true, WatchInterval: time.Hour}
+	ctx, handler, err := NewHandler(&config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+	defer ctx.Close()
+
+	poll := func() []string {
+		results, err := ctx.Watcher.Poll()
+		assert.NoError(t, err)
+		var actions []string
+		for _, result := range results {
+			actions = append(actions, fmt.Sprintf("%s:%s%s", result.File, result.Action, result.Status))
+		}
+		return actions
+	}
+	status := func(path string) int {
+		resp, err := http.Get(fmt.Sprintf("%s%s", ts.URL, path))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Empty(t, poll())
+	assert.Equal(t, 200, status("/foo"))
+
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte(strings.Replace(postConfigSimplePath, "200", "202", 1)), 0o644)
+	os.MkdirAll(filepath.Join(dir, "bar"), 0o755)
+	os.WriteFile(filepath.Join(dir, "bar", "bar.json"), []byte(postConfigQueryMatcher), 0o644)
+	assert.Equal(t, []string{"bar/bar.json:createdOK", "foo.json:updatedOK"}, poll())
+	assert.Equal(t, 202, status("/foo"))
+	assert.Equal(t, 200, status("/bar?id=3"))
+
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte(`{"method": "FETCH"}`), 0o644)
+	assert.Equal(t, []string{"foo.json:FAILED"}, poll())
+	assert.Equal(t, 202, status("/foo"))
+
+	os.Remove(filepath.Join(dir, "foo.json"))
+	os.WriteFile(filepath.Join(dir, "bar", "_defaults.json"), []byte(`{"pathPrefix": "/v2"}`), 0o644)
+	assert.Equal(t, []string{"bar/bar.json:createdOK", "bar/bar.json:deletedOK", "foo.json:deletedOK"}, poll())
+	assert.Equal(t, 500, status("/foo"))
+	assert.Equal(t, 200, status("/v2/bar?id=3"))
+}
+
+func Test_Api_WatchImport_WithoutAutoImport(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "foo.json"), []byte(postConfigSimplePath), 0o644)
+	config := model.Config{DBType: "InMemory", ImportDir: dir, WatchImport: true, WatchInterval: time.Hour}
+	assert.NoError(t, config.Validate())
+	ctx, handler, err := NewHandler(&config)
+	assert.NoError(t, err)
+	defer ctx.Close()
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/foo", ts.URL))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	results, err := ctx.Watcher.Poll()
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func Test_Api_ImportOpenAPI(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	resp, err := http.Post(fmt.Sprintf("%s/config/openapi?mode=upsert", ts.URL), "application/json", bytes.NewBufferString(openAPIDocument))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+		assert.Equal(t, model.ActionCreated, result.Action)
+	}
+
+	resp, _ = http.Get(fmt.Sprintf("%s/orders/42", ts.URL))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"id":42,"status":"open"}`, buf.String())
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/enable?name=getOrder-404", ts.URL), "", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	resp, _ = http.Post(fmt.Sprintf("%s/config/disable?name=getOrder-200", ts.URL), "", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	resp, _ = http.Get(fmt.Sprintf("%s/orders/42", ts.URL))
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/openapi", ts.URL), "application/json", bytes.NewBufferString(`{"swagger": "2.0"}`))
+	assert.Equal(t, 400, resp.StatusCode)
+	resp, _ = http.Post(fmt.Sprintf("%s/config/openapi?paths=template", ts.URL), "application/json", bytes.NewBufferString(openAPIDocument))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_ImportPostman(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	collection := `{
+  "info": { "name": "Shop", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json" },
+  "item": [{
+    "name": "Get invoice",
+    "request": { "method": "GET", "url": "{{baseUrl}}/invoices/7?lang=en" },
+    "response": [{ "name": "Found", "code": 200, "body": "{\"id\": 7}" }]
+  }]
+}`
+	resp, err := http.Post(fmt.Sprintf("%s/config/postman?filename=shop.json", ts.URL), "application/json", bytes.NewBufferString(collection))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, results, 1)
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/invoices/7?lang=en", ts.URL))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"id":7}`, buf.String())
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/postman", ts.URL), "application/json", bytes.NewBufferString(`{"info": {}}`))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_ImportPosting(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	request := `
+name: Get receipt
+method: GET
+url: $BASE_URL/receipts/3
+headers:
+  - { name: X-Client, value: tests }
+`
+	resp, err := http.Post(fmt.Sprintf("%s/config/posting?filename=receipt.posting.yaml", ts.URL), "application/yaml", bytes.NewBufferString(request))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, results, 1)
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/receipts/3", ts.URL), nil)
+	req.Header.Set("X-Client", "tests")
+	resp, _ = http.DefaultClient.Do(req)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func Test_Api_HAR(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(`{"method": "GET", "path": "/parcels/5", "responseStatus": 200, "responseBody": {"id": 5}}`))
+	resp, _ := http.Get(fmt.Sprintf("%s/parcels/5?track=true", ts.URL))
+	resp.Body.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/config/journal?format=har", ts.URL))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	archive := new(bytes.Buffer)
+	_, _ = archive.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, archive.String(), fmt.Sprintf(`"url":"%s/parcels/5?track=true"`, ts.URL))
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/config/reset", ts.URL), nil)
+	resp, _ = http.DefaultClient.Do(req)
+	assert.Equal(t, 200, resp.StatusCode)
+	resp, _ = http.Post(fmt.Sprintf("%s/config/har?filename=journal.har", ts.URL), "application/json", archive)
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, results, 1)
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/parcels/5?track=true", ts.URL))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"id":5}`, buf.String())
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/har", ts.URL), "application/json", bytes.NewBufferString(`[]`))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_WireMock(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	mapping := `{"name": "get-cart", "request": {"method": "GET", "urlPath": "/carts/3", "queryParameters": {"full": {"equalTo": "true"}}},
+"response": {"status": 200, "jsonBody": {"id": 3}, "fixedDelayMilliseconds": 50}}`
+	resp, err := http.Post(fmt.Sprintf("%s/config/wiremock?filename=cart.json", ts.URL), "application/json", bytes.NewBufferString(mapping))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, results, 1)
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+	assert.Equal(t, []string{"Ignored WireMock construct [response.fixedDelayMilliseconds]"}, results[0].Warnings)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/carts/3?full=true", ts.URL))
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/wiremock?name=get-cart", ts.URL))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"mappings": [{"name": "get-cart", "request": {"method": "GET", "urlPath": "/carts/3", "queryParameters": {"full": {"equalTo": "true"}}},
+"response": {"status": 200, "jsonBody": {"id": 3}, "headers": {"Content-Type": "application/json"}}}]}`, buf.String())
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/wiremock?filename=cart.json", ts.URL), "application/json", bytes.NewBufferString(`{"response": {}}`))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_Pact(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	contract := `{
+  "consumer": { "name": "web" },
+  "provider": { "name": "stock" },
+  "interactions": [
+    { "description": "get stock", "request": { "method": "GET", "path": "/stock/1" }, "response": { "status": 200, "body": { "count": 3 } } },
+    { "description": "reserve stock", "request": { "method": "POST", "path": "/stock/1/reserve", "body": { "count": 1 } }, "response": { "status": 201 } }
+  ]
+}`
+	resp, err := http.Post(fmt.Sprintf("%s/config/pact?filename=web-stock.json", ts.URL), "application/json", bytes.NewBufferString(contract))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, results, 2)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/stock/1", ts.URL))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"count":3}`, buf.String())
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/pact/verify", ts.URL), "application/json", bytes.NewBufferString(contract))
+	buf.Reset()
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"consumer": "web", "provider": "stock", "verified": false, "exercised": 1, "total": 2, "interactions": [
+{"description": "get stock", "requests": 1, "exercised": true}, {"description": "reserve stock", "requests": 0, "exercised": false}]}`, buf.String())
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/pact/verify", ts.URL), "application/json", bytes.NewBufferString(`{}`))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_Snippets(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	for _, input := range []string{postConfigRegexPath, postConfigQueryMatcher, postConfigHeaderMatcher, postConfigBodyMatcher} {
+		resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(input))
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/config/snippets", ts.URL))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var snippets []snippet.Snippet
+	_ = json.NewDecoder(resp.Body).Decode(&snippets)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, snippets, 4)
+	for _, s := range snippets {
+		req, _ := http.NewRequest(s.Method, s.URL, strings.NewReader(s.Body))
+		for name, value := range s.Headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		assert.Less(t, resp.StatusCode, 300, s.Curl)
+	}
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/snippets?format=curl&id=%d", ts.URL, snippets[0].MockID))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, fmt.Sprintf("# %d %s %s\n%s\n", snippets[0].MockID, snippets[0].Method, snippets[0].URL, snippets[0].Curl), buf.String())
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/snippets?format=xml", ts.URL))
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func Test_Api_Lint(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	for _, input := range []string{
+		`{"method": "GET", "path": "/lint/users", "responseStatus": 200}`,
+		`{"method": "GET", "path": "/lint/users", "requestQueryMatchers": [{"key": "id", "value": "1"}], "responseStatus": 200}`,
+		`{"method": "GET", "regexPath": "^/lint/.*$", "responseStatus": 200}`,
+	} {
+		resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(input))
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/config/lint", ts.URL))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var issues []lint.Issue
+	_ = json.NewDecoder(resp.Body).Decode(&issues)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	var rules []string
+	for _, issue := range issues {
+		rules = append(rules, issue.Rule)
+	}
+	assert.Equal(t, []string{lint.RuleShadowed, lint.RuleRegexOverlap}, rules)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/lint", ts.URL), "application/json", nil)
+	assert.Equal(t, 405, resp.StatusCode)
+}
+
+func Test_Api_Match(t *testing.T) {
+	ts := runTestServer()
+	defer ts.Close()
+
+	for _, input := range []string{
+		`{"method": "POST", "path": "/match/users", "requestHeaderMatchers": [{"key": "X-Id", "value": "2"}], "responseStatus": 200}`,
+		`{"method": "POST", "path": "/match/users", "requestBodyMatchers": [{"key": "$.name", "value": "John"}], "responseStatus": 201}`,
+	} {
+		resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(input))
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	match := func(input string) model.MatchReport {
+		resp, err := http.Post(fmt.Sprintf("%s/config/match", ts.URL), "application/json", bytes.NewBufferString(input))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode)
+		var report model.MatchReport
+		_ = json.NewDecoder(resp.Body).Decode(&report)
+		return report
+	}
+
+	report := match(`{"method": "POST", "url": "/match/users?id=1", "headers": {"X-Id": ["1"]}, "body": "{\"name\": \"John\"}"}`)
+	assert.Equal(t, "/match/users", report.Path)
+	assert.Equal(t, 201, report.Mock.ResponseStatus)
+	assert.Len(t, report.Candidates, 2)
+	assert.False(t, report.Candidates[0].Matched)
+	assert.Equal(t, []model.MatcherResult{{Type: model.MatcherHeader, Key: "X-Id", Expected: "2", Actual: []string{"1"}, Matched: false}}, report.Candidates[0].Matchers)
+	assert.True(t, report.Candidates[1].Matched)
+	assert.Equal(t, []model.MatcherResult{{Type: model.MatcherBody, Key: "$.name", Expected: "John", Actual: []string{"John"}, Matched: true}}, report.Candidates[1].Matchers)
+
+	report = match(`{"method": "POST", "url": "/match/users"}`)
+	assert.Nil(t, report.Mock)
+	assert.Equal(t, "not matched", report.Message)
+	assert.Len(t, report.Candidates, 2)
+
+	report = match(`{"url": "/match/orders"}`)
+	assert.Equal(t, "GET", report.Method)
+	assert.Equal(t, routing.NoMockOnRoute, report.Message)
+	assert.Empty(t, report.Candidates)
+
+	for _, input := range []string{
+		`{"method": "GET", "regexPath": "^/match/orders/\\d+$", "responseStatus": 202}`,
+		`{"method": "GET", "path": "/match/orders/1", "responseStatus": 200}`,
+	} {
+		resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(input))
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+	report = match(`{"url": "/match/orders/1"}`)
+	assert.Equal(t, 202, report.Mock.ResponseStatus)
+	assert.Len(t, report.Candidates, 2)
+
+	resp, _ := http.Get(fmt.Sprintf("%s/config/journal", ts.URL))
+	var entries []model.JournalEntry
+	_ = json.NewDecoder(resp.Body).Decode(&entries)
+	resp.Body.Close()
+	assert.Empty(t, entries)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/match", ts.URL), "application/json", bytes.NewBufferString(`{"url": 1}`))
+	assert.Equal(t, 400, resp.StatusCode)
+	resp, _ = http.Get(fmt.Sprintf("%s/config/match", ts.URL))
+	assert.Equal(t, 405, resp.StatusCode)
+}
+
+func Test_ReadConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "mockery.yml")
+	os.WriteFile(file, []byte("dbType: SqLite\nport: 8081\njournalSize: 10\ndbConfig:\n  sqlite:\n    connStr: file:file.db\n"), 0o644)
+
+	config, err := ReadConfig(file, Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SqLite, config.DBType)
+	assert.Equal(t, "file:file.db", config.DBConfig.SqLite.ConnectionString)
+	assert.Equal(t, model.ImportDir, config.ImportDir)
+
+	t.Setenv("MOCKERY_PORT", "8082")
+	t.Setenv("MOCKERY_DB_CONFIG_SQLITE_CONN_STR", "file:env.db")
+	config, err = ReadConfig(file, Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, 8082, config.Port)
+	assert.Equal(t, 10, config.JournalSize)
+	assert.Equal(t, "file:env.db", config.DBConfig.SqLite.ConnectionString)
+
+	config, err = ReadConfig(file, Overrides{Port: 8083, ConnStr: "file:flag.db"})
+	assert.NoError(t, err)
+	assert.Equal(t, 8083, config.Port)
+	assert.Equal(t, "file:flag.db", config.DBConfig.SqLite.ConnectionString)
+
+	_, err = ReadConfig(file, Overrides{DBType: "Postgres"})
+	assert.ErrorContains(t, err, model.MissingConnectionString)
+
+	t.Setenv("MOCKERY_JOURNAL_SIZE", "many")
+	_, err = ReadConfig(file, Overrides{})
+	assert.ErrorContains(t, err, "MOCKERY_JOURNAL_SIZE")
+
+	_, err = ReadConfig(filepath.Join(dir, "missing.yml"), Overrides{})
+	assert.Error(t, err)
+}
+
+func Test_Api_Settings(t *testing.T) {
+	config := model.Config{DBType: model.SqLite, DBConfig: model.DBConfig{SqLite: model.DBParams{ConnectionString: "file:settings?mode=memory&cache=shared"}}}
+	assert.NoError(t, config.Validate())
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/config/settings", ts.URL))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var settings model.Config
+	_ = json.NewDecoder(resp.Body).Decode(&settings)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, model.SqLite, settings.DBType)
+	assert.Equal(t, config.Port, settings.Port)
+	assert.Equal(t, model.RedactedValue, settings.DBConfig.SqLite.ConnectionString)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/settings", ts.URL), "application/json", nil)
+	assert.Equal(t, 405, resp.StatusCode)
+}
+
+func Test_Serve_Shutdown(t *testing.T) {
+	exportDir := t.TempDir()
+	config := model.Config{DBType: "InMemory", ExportDir: exportDir, ExportOnShutdown: true}
+	assert.NoError(t, config.Validate())
+	ctx, handler, err := NewHandler(&config)
+	assert.NoError(t, err)
+	_, err = ctx.MockService.Add(model.Mock{Method: "GET", Path: "/shutdown", ResponseStatus: 200})
+	assert.NoError(t, err)
+	_, err = ctx.MockService.Add(model.Mock{Method: "GET", Path: "/shutdown", SessionID: "s1", ResponseStatus: 200})
+	assert.NoError(t, err)
+
+	started := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(rw http.ResponseWriter, req *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		rw.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/", handler)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	done, cancel := stdcontext.WithCancel(t.Context())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(done, ctx, &http.Server{Handler: mux}, listener)
+	}()
+
+	slow := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://%s/slow", listener.Addr()))
+		if err != nil {
+			slow <- 0
+			return
+		}
+		resp.Body.Close()
+		slow <- resp.StatusCode
+	}()
+	<-started
+	cancel()
+
+	assert.Equal(t, 200, <-slow)
+	assert.NoError(t, <-served)
+	files, _ := os.ReadDir(exportDir)
+	assert.Len(t, files, 1)
+	_, err = ctx.MockService.List(model.Selector{})
+	assert.Error(t, err)
+	_, err = http.Get(fmt.Sprintf("http://%s/health", listener.Addr()))
+	assert.Error(t, err)
+}
+
+func Test_Serve_TLS(t *testing.T) {
+	dir := t.TempDir()
+	config := model.Config{DBType: "InMemory", TLS: model.TLS{Enabled: true, Dir: dir}}
+	assert.NoError(t, config.Validate())
+	config.Port, config.TLS.Port = 0, 0 // random ports
+	ctx, handler, err := NewHandler(&config)
+	assert.NoError(t, err)
+	listeners, err := Listen(&config)
+	assert.NoError(t, err)
+	assert.Len(t, listeners, 2)
+
+	done, cancel := stdcontext.WithCancel(t.Context())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(done, ctx, &http.Server{Handler: handler}, listeners...)
+	}()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-served)
+	}()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/health", listeners[0].Addr()))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	ca, err := os.ReadFile(filepath.Join(dir, cert.CAFile))
+	assert.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}, ForceAttemptHTTP2: true}}
+	_, port, _ := net.SplitHostPort(listeners[1].Addr().String())
+	resp, err = client.Get(fmt.Sprintf("https://localhost:%s/health", port))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor)
+
+	_, err = http.Get(fmt.Sprintf("https://localhost:%s/health", port))
+	assert.Error(t, err)
+}
+
+func Test_Serve_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	config := model.Config{DBType: "InMemory", TLS: model.TLS{Enabled: true, Dir: dir, ClientAuth: model.ClientAuthRequest}}
+	assert.NoError(t, config.Validate())
+	config.Port, config.TLS.Port = 0, 0 // random ports
+	ctx, handler, err := NewHandler(&config)
+	assert.NoError(t, err)
+	listeners, err := Listen(&config)
+	assert.NoError(t, err)
+
+	done, cancel := stdcontext.WithCancel(t.Context())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(done, ctx, &http.Server{Handler: handler}, listeners...)
+	}()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-served)
+	}()
+
+	for _, mock := range []string{
+		`{"method": "GET", "path": "/mtls/orders", "responseStatus": 403}`,
+		`{"method": "GET", "path": "/mtls/orders", "requestCertMatchers": [{"key": "cn", "value": "billing"}], "responseStatus": 200}`,
+	} {
+		resp, err := http.Post(fmt.Sprintf("http://%s/config", listeners[0].Addr()), "application/json", bytes.NewBufferString(mock))
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	ca, err := os.ReadFile(filepath.Join(dir, cert.CAFile))
+	assert.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca)
+	_, port, _ := net.SplitHostPort(listeners[1].Addr().String())
+	get := func(name string) (*http.Response, error) {
+		tlsConfig := &tls.Config{RootCAs: roots}
+		if len(name) > 0 {
+			certFile, keyFile, err := cert.GenerateClient(dir, name, name, nil)
+			assert.NoError(t, err)
+			pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+			assert.NoError(t, err)
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, ForceAttemptHTTP2: true}}
+		return client.Get(fmt.Sprintf("https://localhost:%s/mtls/orders", port))
+	}
+
+	for _, tc := range []struct {
+		name   string
+		status int
+	}{{"billing", 200}, {"orders", 403}, {"", 403}} {
+		resp, err := get(tc.name)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		assert.Equal(t, tc.status, resp.StatusCode, tc.name)
+	}
+
+	resp, _ := http.Get(fmt.Sprintf("http://%s/config/journal", listeners[0].Addr()))
+	var entries []model.JournalEntry
+	_ = json.NewDecoder(resp.Body).Decode(&entries)
+	resp.Body.Close()
+	var names []string
+	for _, entry := range entries {
+		if entry.Request.ClientCert != nil {
+			names = append(names, entry.Request.ClientCert.CommonName)
+			assert.Equal(t, "Mockery CA", entry.Request.ClientCert.Issuer)
+		}
+	}
+	assert.ElementsMatch(t, []string{"billing", "orders"}, names)
+
+	resp, _ = http.Post(fmt.Sprintf("http://%s/config/match", listeners[0].Addr()), "application/json", bytes.NewBufferString(`{"url": "/mtls/orders", "clientCert": {"commonName": "billing"}}`))
+	var report model.MatchReport
+	_ = json.NewDecoder(resp.Body).Decode(&report)
+	resp.Body.Close()
+	if assert.NotNil(t, report.Mock) {
+		assert.Equal(t, 200, report.Mock.ResponseStatus)
+	}
+
+	config = model.Config{DBType: "InMemory", TLS: model.TLS{Enabled: true, Dir: dir, ClientAuth: model.ClientAuthRequire}}
+	assert.NoError(t, config.Validate())
+	config.Port, config.TLS.Port = 0, 0
+	required, err := Listen(&config)
+	assert.NoError(t, err)
+	defer func() {
+		for _, listener := range required {
+			listener.Close()
+		}
+	}()
+	go http.Serve(required[1], handler)
+	_, port, _ = net.SplitHostPort(required[1].Addr().String())
+	_, err = get("")
+	assert.Error(t, err)
+	resp, err = get("billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func Test_NewHandler_Error(t *testing.T) {
+	config := model.Config{DBType: "Postgres", DBConfig: model.DBConfig{Postgres: model.DBParams{ConnectionString: "postgres://mockery@127.0.0.1:1/mockery?connect_timeout=1"}}}
+	assert.NoError(t, config.Validate())
+	_, _, err := NewHandler(&config)
+	assert.ErrorContains(t, err, db.FailedToConnect)
+}
+
+func Test_Api_Contract(t *testing.T) {
+	document := filepath.Join(t.TempDir(), "openapi.json")
+	os.WriteFile(document, []byte(openAPIDocument), 0o644)
+	config := model.Config{DBType: "InMemory", Contract: model.Contract{Document: document, Requests: true, Responses: true}}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(`{"method": "GET", "path": "/orders/1", "responseStatus": 500, "responseBody": {}}`))
+	buf := new(bytes.Buffer)
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, buf.String(), "ResponseStatus - not documented for getOrder: [500]")
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(`{"method": "GET", "path": "/orders/1", "responseStatus": 404, "responseBody": {"error": 1}}`))
+	buf.Reset()
+	_, _ = buf.ReadFrom(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, buf.String(), "ResponseBody - $.error: expected string, got number")
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(`{"method": "GET", "path": "/orders/1", "responseStatus": 200, "responseBody": {"id": 1}}`))
+	assert.Equal(t, 201, resp.StatusCode)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/upload?filename=orders.json", ts.URL), "application/json", bytes.NewBufferString(`{"method": "GET", "path": "/orders/2", "responseStatus": 500, "responseBody": {}}`))
+	var results []model.ImportResult
+	_ = json.NewDecoder(resp.Body).Decode(&results)
+	resp.Body.Close()
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, model.ImportFailed, results[0].Status)
+		assert.Contains(t, results[0].Errors, "ResponseStatus - not documented for getOrder: [500]")
+	}
+
+	resp, _ = http.Get(fmt.Sprintf("%s/orders/1", ts.URL))
+	assert.Equal(t, 200, resp.StatusCode)
+	resp, _ = http.Post(fmt.Sprintf("%s/orders/1", ts.URL), "application/json", nil)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/journal", ts.URL))
+	var entries []model.JournalEntry
+	_ = json.NewDecoder(resp.Body).Decode(&entries)
+	resp.Body.Close()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, &model.ContractResult{Operation: "getOrder", Valid: true}, entries[0].Contract)
+	assert.Nil(t, entries[1].Contract)
+}
+
+func Test_Api_ContractRequests(t *testing.T) {
+	document := filepath.Join(t.TempDir(), "openapi.yaml")
+	os.WriteFile(document, []byte(openAPIContract), 0o644)
+	config := model.Config{DBType: "InMemory", Contract: model.Contract{Document: document, Requests: true}}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, _ := http.Post(fmt.Sprintf("%s/config", ts.URL), "application/json", bytes.NewBufferString(`{"method": "POST", "path": "/orders", "responseStatus": 500, "responseBody": {}}`))
+	assert.Equal(t, 201, resp.StatusCode)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/orders", ts.URL), "application/json", bytes.NewBufferString(`{"item": 1}`))
+	var result model.ContractResult
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	resp.Body.Close()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, model.ContractResult{Operation: "createOrder", Errors: []string{"$.item: expected string, got number"}}, result)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/orders", ts.URL), "application/json", bytes.NewBufferString(`{"item": "book"}`))
+	assert.Equal(t, 500, resp.StatusCode)
+
+	resp, _ = http.Get(fmt.Sprintf("%s/config/journal", ts.URL))
+	var entries []model.JournalEntry
+	_ = json.NewDecoder(resp.Body).Decode(&entries)
+	resp.Body.Close()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, []string{"$.item: expected string, got number"}, entries[0].Contract.Errors)
+	assert.True(t, entries[1].Contract.Valid)
+
+	config.Contract.Document = filepath.Join(t.TempDir(), "missing.yaml")
+	assert.Panics(t, func() { SetupServer(&config) })
+}
+
+func Test_Api_UploadDownload(t *testing.T) {
+	source := runTestServer()
+	defer source.Close()
+	target := runTestServer()
+	defer target.Close()
+
+	for _, input := range []string{postConfigTaggedBilling, postConfigTaggedOrders} {
+		resp, _ := http.Post(fmt.Sprintf("%s/config", source.URL), "application/json", bytes.NewBufferString(input))
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+
+	for _, archive := range []string{"zip", "tar.gz"} {
+		t.Run("Download and upload "+archive, func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/config/download?tag=suite&format=yaml&archive=%s", source.URL, archive))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			contents, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.Equal(t, 200, resp.StatusCode)
+			assert.Contains(t, resp.Header.Get("Content-Disposition"), "mocks."+archive)
+
+			resp, err = http.Post(fmt.Sprintf("%s/config/upload?mode=upsert&filename=mocks.%s", target.URL, archive), "application/octet-stream", bytes.NewReader(contents))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			var results []model.ImportResult
+			_ = json.NewDecoder(resp.Body).Decode(&results)
+			resp.Body.Close()
+			assert.Equal(t, 200, resp.StatusCode)
+			assert.Len(t, results, 2)
+			for _, result := range results {
+				assert.Equal(t, model.ImportOK, result.Status, result.Errors)
+				assert.Equal(t, ".yaml", filepath.Ext(result.File))
+			}
+		})
+	}
+
+	resp, _ := http.Get(fmt.Sprintf("%s/config/list?tag=suite", target.URL))
+	var mocks []model.Mock
+	_ = json.NewDecoder(resp.Body).Decode(&mocks)
+	resp.Body.Close()
+	assert.Len(t, mocks, 2)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/upload", target.URL), "application/yaml", bytes.NewBufferString(yamlMockList))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/upload", target.URL), "text/plain", bytes.NewBufferString(yamlMockList))
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, _ = http.Post(fmt.Sprintf("%s/config/upload?filename=mocks.zip", target.URL), "application/octet-stream", bytes.NewBufferString(yamlMockList))
+	assert.Equal(t, 400, resp.StatusCode)
+
+	tooLarge := bytes.Repeat([]byte(" "), int(util.MaxUploadSize)+1)
+	resp, _ = http.Post(fmt.Sprintf("%s/config/upload", target.URL), "application/yaml", bytes.NewReader(tooLarge))
+	assert.Equal(t, 413, resp.StatusCode)
+
+	form := new(bytes.Buffer)
+	writer := multipart.NewWriter(form)
+	part, _ := writer.CreateFormFile("file", "mocks.yaml")
+	part.Write(tooLarge)
+	writer.Close()
+	resp, _ = http.Post(fmt.Sprintf("%s/config/upload", target.URL), writer.FormDataContentType(), form)
+	assert.Equal(t, 413, resp.StatusCode)
+}
+
+func Test_Api_UploadNamespaces(t *testing.T) {
+	config := model.Config{DBType: "InMemory"}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, handler := SetupServer(&config)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	do := func(method string, url string, namespace string, body io.Reader) *http.Response {
+		req, _ := http.NewRequest(method, fmt.Sprintf("%s%s", ts.URL, url), body)
+		req.Header.Set(model.DefaultNamespaceHeader, namespace)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return resp
+	}
+	list := func(namespace string) []model.Mock {
+		resp := do("GET", "/config/list", namespace, nil)
+		defer resp.Body.Close()
+		var mocks []model.Mock
+		_ = json.NewDecoder(resp.Body).Decode(&mocks)
+		return mocks
+	}
+
+	for _, input := range []string{postConfigTaggedBilling, postConfigTaggedOrders} {
+		resp := do("POST", "/config", "billing", bytes.NewBufferString(input))
+		resp.Body.Close()
+		assert.Equal(t, 201, resp.StatusCode)
+	}
+	billing := list("billing")
+
+	resp := do("GET", "/config/download?archive=zip", "billing", nil)
+	contents, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp = do("POST", "/config/upload?mode=create&filename=mocks.zip", "orders", bytes.NewReader(contents))
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	assert.Equal(t, billing, list("billing"))
+	orders := list("orders")
+	if assert.Len(t, orders, 2) {
+		for i := range orders {
+			assert.Equal(t, "orders", orders[i].Namespace)
+			assert.NotContains(t, []int64{billing[0].ID, billing[1].ID}, orders[i].ID)
+		}
+	}
+}
+
 var (
+	yamlMocks = `method: GET
+path: /yaml/first
+responseStatus: 200
+responseBody:
+  first: true
+---
+method: POST
+path: /yaml/second
+requestBodyMatchers:
+  - key: $.id
+    value: 2
+responseStatus: 201
+responseBody: {}
+---
+method: FETCH
+path: /yaml/invalid
+responseStatus: 200
+`
+	yamlMockList = `- method: GET
+  regexPath: ^/yaml/list/\d+$
+  responseStatus: 201
+  responseBody:
+    items:
+      - id: 1
+      - id: 2
+`
+	postConfigSessionPath = `{
+		"method": "GET",
+		"path": "/foo",
+		"responseStatus": 200,
+		"responseBody": { "foo": true, "bar": { "id": 6 } }
+	}`
+	postConfigTaggedBilling = `{
+		"name": "invoices",
+		"description": "List invoices",
+		"tags": ["billing", "suite"],
+		"method": "GET",
+		"path": "/invoices",
+		"responseStatus": 200,
+		"responseBody": { "invoices": [] }
+	}`
+	postConfigTaggedOrders = `{
+		"name": "orders",
+		"tags": ["orders", "suite"],
+		"method": "GET",
+		"path": "/orders",
+		"responseStatus": 200,
+		"responseBody": { "orders": [] }
+	}`
 	postConfigMissingPath = `{
 		"method": "GET",
 		"responseStatus": 200,
@@ -118,6 +1499,37 @@ var (
 		"responseStatus": 200,
 		"responseBody": { "foo": true, "bar": { "id": 1 } }
 	}`
+	openAPIDocument = `{
+		"openapi": "3.1.0",
+		"paths": {
+			"/orders/{id}": {
+				"get": {
+					"operationId": "getOrder",
+					"responses": {
+						"200": { "content": { "application/json": { "example": { "id": 42, "status": "open" } } } },
+						"404": { "content": { "application/json": { "schema": { "type": "object", "properties": { "error": { "type": "string" } } } } } }
+					}
+				}
+			}
+		}
+	}`
+	openAPIContract = `
+openapi: 3.0.3
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                item: { type: string }
+      responses:
+        '500': { description: Error }
+`
 	postConfigSimplePath = `{
 		"method": "GET",
 		"path": "/foo",
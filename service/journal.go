@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+type JournalInt interface {
+	Record(entry model.JournalEntry) model.JournalEntry
+	List(filter model.JournalFilter) []model.JournalEntry
+	Clear(filter model.JournalFilter) int
+}
+
+// JournalService keeps the most recent requests in memory. Oldest entries are dropped once size is reached.
+// The configured size is always positive, see model.Config.Validate.
+type JournalService struct {
+	lock    sync.RWMutex
+	entries []model.JournalEntry
+	nextID  int64
+	size    int
+}
+
+func InitJournalService(size int) *JournalService {
+	return &JournalService{size: size}
+}
+
+func (js *JournalService) Record(entry model.JournalEntry) model.JournalEntry {
+	js.lock.Lock()
+	defer js.lock.Unlock()
+	js.nextID++
+	entry.ID = js.nextID
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	js.entries = append(js.entries, entry)
+	if js.size > 0 && len(js.entries) > js.size {
+		js.entries = js.entries[len(js.entries)-js.size:]
+	}
+	return entry
+}
+
+func (js *JournalService) List(filter model.JournalFilter) []model.JournalEntry {
+	js.lock.RLock()
+	defer js.lock.RUnlock()
+	entries := []model.JournalEntry{}
+	for i := range js.entries {
+		if filter.Matches(js.entries[i]) {
+			entries = append(entries, js.entries[i])
+		}
+	}
+	return entries
+}
+
+func (js *JournalService) Clear(filter model.JournalFilter) int {
+	js.lock.Lock()
+	defer js.lock.Unlock()
+	kept := js.entries[:0]
+	for i := range js.entries {
+		if !filter.Matches(js.entries[i]) {
+			kept = append(kept, js.entries[i])
+		}
+	}
+	removed := len(js.entries) - len(kept)
+	js.entries = kept
+	return removed
+}
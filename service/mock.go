@@ -2,58 +2,217 @@
 package service
 
 import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"slices"
+
 	"github.com/rromanowicz/mockery/db"
 	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/openapi"
+	"github.com/rromanowicz/mockery/util"
 	"gorm.io/gorm"
 )
 
 type MockInt interface {
-	Get(method string, path string) ([]model.Mock, error)
-	GetByIds(ids []int64) ([]model.Mock, error)
+	Get(namespace string, sessionID string, method string, path string) ([]model.Mock, error)
+	GetByIds(namespace string, sessionID string, ids []int64) ([]model.Mock, error)
 	Add(mock model.Mock) (model.Mock, error)
-	Delete(id int64) error
-	List() ([]model.Mock, error)
-	Import() ([]string, error)
-	Export() ([]string, error)
-	GetRegexpMatchers(method string) ([]model.RegexMatcher, error)
+	Delete(namespace string, id int64) error
+	List(selector model.Selector) ([]model.Mock, error)
+	DeleteAll(selector model.Selector) (int, error)
+	SetEnabled(selector model.Selector, enabled bool) (int, error)
+	Import(dir string, options model.ImportOptions) ([]model.ImportResult, error)
+	Export(selector model.Selector, dir string, format string) ([]string, error)
+	ExportAll(format string) ([]string, error)
+	Upload(files []model.MockFile, options model.ImportOptions) ([]model.ImportResult, error)
+	ImportOpenAPI(contents []byte, openapiOptions openapi.Options, options model.ImportOptions) ([]model.ImportResult, error)
+	ImportResults(results []model.ImportResult, options model.ImportOptions) ([]model.ImportResult, error)
+	GetRegexpMatchers(namespace string, sessionID string, method string) ([]model.RegexMatcher, error)
+	Reset(namespace string) (int, error)
+	Validate(mock model.Mock) (bool, []string)
 }
 
 type MockService struct {
-	Repository db.MockRepoInt
+	Repository    db.MockRepoInt
+	ImportDir     string
+	ExportDir     string
+	ImportFolders string
+	ImportMode    string
+	// Contract checks added and imported mocks against the API contract, nil without contract validation.
+	Contract func(mock model.Mock) []string
 }
 
-func InitMockService(repo db.MockRepoInt, dbDriverFn func(str string) gorm.Dialector, dbParams model.DBParams) MockService {
-	return MockService{Repository: repo.InitDB(dbDriverFn, dbParams)}
+func InitMockService(repo db.MockRepoInt, dbDriverFn func(str string) gorm.Dialector, dbParams model.DBParams, config *model.Config) (MockService, error) {
+	repository, err := repo.InitDB(dbDriverFn, dbParams)
+	return MockService{
+		Repository:    repository,
+		ImportDir:     config.ImportDir,
+		ExportDir:     config.ExportDir,
+		ImportFolders: config.ImportFolders,
+		ImportMode:    config.ImportMode,
+	}, err
 }
 
-func (ms MockService) Get(method string, path string) ([]model.Mock, error) {
-	return ms.Repository.FindByMethodAndPath(method, path)
+func (ms MockService) Get(namespace string, sessionID string, method string, path string) ([]model.Mock, error) {
+	return ms.Repository.FindByMethodAndPath(namespace, sessionID, method, path)
 }
 
-func (ms MockService) GetByIds(ids []int64) ([]model.Mock, error) {
-	return ms.Repository.FindByIDs(ids)
+func (ms MockService) GetByIds(namespace string, sessionID string, ids []int64) ([]model.Mock, error) {
+	return ms.Repository.FindByIDs(namespace, sessionID, ids)
 }
 
 func (ms MockService) Add(mock model.Mock) (model.Mock, error) {
 	return ms.Repository.Save(mock)
 }
 
-func (ms MockService) Delete(id int64) error {
-	return ms.Repository.DeleteByID(id)
+func (ms MockService) Delete(namespace string, id int64) error {
+	return ms.Repository.DeleteByID(namespace, id)
+}
+
+func (ms MockService) List(selector model.Selector) ([]model.Mock, error) {
+	return ms.Repository.FindBySelector(selector)
+}
+
+func (ms MockService) DeleteAll(selector model.Selector) (int, error) {
+	return ms.Repository.DeleteBySelector(selector)
+}
+
+func (ms MockService) SetEnabled(selector model.Selector, enabled bool) (int, error) {
+	return ms.Repository.SetDisabled(selector, !enabled)
+}
+
+// Import reads mocks from the configured import directory or its subdirectory dir.
+func (ms MockService) Import(dir string, options model.ImportOptions) ([]model.ImportResult, error) {
+	importDir, err := util.ResolveDir(ms.ImportDir, dir)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	if dir = filepath.ToSlash(filepath.Clean(dir)); len(dir) != 0 && dir != "." {
+		options.Dir = dir
+	}
+	return ms.importDir(importDir, ms.importOptions(options))
+}
+
+func (ms MockService) importDir(importDir string, options model.ImportOptions) ([]model.ImportResult, error) {
+	results, err := db.ImportMocks(importDir, options)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	return ms.Repository.ApplyImport(ms.checkContract(results), options)
+}
+
+// Upload imports uploaded mock files and archives following the same rules as Import.
+// Ids and sessions of the files are dropped, as are namespaces unless folders are imported as namespaces,
+// so downloaded mocks land in the namespace of the request. Reported file names are relative to the uploaded archive.
+func (ms MockService) Upload(files []model.MockFile, options model.ImportOptions) ([]model.ImportResult, error) {
+	dir, err := os.MkdirTemp("", "mockery-upload-")
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err = util.WriteFiles(dir, files); err != nil {
+		return []model.ImportResult{}, err
+	}
+	options = ms.importOptions(options)
+	results, err := db.ImportMocks(dir, options)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	for i := range results {
+		if mock := results[i].Mock; mock != nil {
+			mock.ID, mock.SessionID = 0, ""
+			if options.Folders != model.FoldersAsNamespace {
+				mock.Namespace = ""
+			}
+		}
+	}
+	results, err = ms.Repository.ApplyImport(ms.checkContract(results), options)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	for i := range results {
+		if file, err := filepath.Rel(dir, results[i].File); err == nil && len(results[i].File) != 0 {
+			results[i].File = filepath.ToSlash(file)
+		}
+	}
+	return results, nil
+}
+
+// ImportOpenAPI generates mocks from an OpenAPI 3 document and imports them following the same rules as Import.
+func (ms MockService) ImportOpenAPI(contents []byte, openapiOptions openapi.Options, options model.ImportOptions) ([]model.ImportResult, error) {
+	results, err := openapi.Read(contents, openapiOptions)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	return ms.Repository.ApplyImport(ms.checkContract(results), ms.importOptions(options))
+}
+
+// ImportResults imports mocks converted from other formats following the same rules as Import.
+func (ms MockService) ImportResults(results []model.ImportResult, options model.ImportOptions) ([]model.ImportResult, error) {
+	return ms.Repository.ApplyImport(ms.checkContract(results), ms.importOptions(options))
+}
+
+// Validate checks mock, including the API contract if configured.
+func (ms MockService) Validate(mock model.Mock) (bool, []string) {
+	if ms.Contract == nil {
+		return mock.Validate()
+	}
+	return mock.Validate(ms.Contract)
+}
+
+// checkContract fails the successful results whose mocks violate the API contract.
+func (ms MockService) checkContract(results []model.ImportResult) []model.ImportResult {
+	if ms.Contract == nil {
+		return results
+	}
+	for i := range results {
+		if results[i].Status != model.ImportOK || results[i].Mock == nil {
+			continue
+		}
+		if errs := ms.Contract(*results[i].Mock); len(errs) != 0 {
+			results[i].Status, results[i].Errors, results[i].Mock = model.ImportFailed, errs, nil
+		}
+	}
+	return results
+}
+
+func (ms MockService) importOptions(options model.ImportOptions) model.ImportOptions {
+	if len(options.Folders) == 0 {
+		options.Folders = ms.ImportFolders
+	}
+	if len(options.Mode) == 0 {
+		options.Mode = cmp.Or(ms.ImportMode, model.ImportCreate)
+	}
+	return options
 }
 
-func (ms MockService) List() ([]model.Mock, error) {
-	return ms.Repository.GetAll()
+// Export writes mocks to the configured export directory or its subdirectory dir.
+func (ms MockService) Export(selector model.Selector, dir string, format string) ([]string, error) {
+	exportDir, err := util.ResolveDir(ms.ExportDir, dir)
+	if err != nil {
+		return []string{}, err
+	}
+	return ms.Repository.Export(exportDir, cmp.Or(format, model.FormatJSON), selector)
 }
 
-func (ms MockService) Import() ([]string, error) {
-	return ms.Repository.Import()
+// ExportAll writes the mocks of all namespaces to the export directory. Session mocks are left out, as they
+// belong to a running test.
+func (ms MockService) ExportAll(format string) ([]string, error) {
+	mocks, err := ms.Repository.GetAll()
+	if err != nil {
+		return []string{}, err
+	}
+	mocks = slices.DeleteFunc(mocks, func(mock model.Mock) bool { return len(mock.SessionID) != 0 })
+	return db.ExportMocks(ms.ExportDir, cmp.Or(format, model.FormatJSON), mocks)
 }
 
-func (ms MockService) Export() ([]string, error) {
-	return ms.Repository.Export()
+func (ms MockService) GetRegexpMatchers(namespace string, sessionID string, method string) ([]model.RegexMatcher, error) {
+	return ms.Repository.GetRegexpMatchers(namespace, sessionID, method)
 }
 
-func (ms MockService) GetRegexpMatchers(method string) ([]model.RegexMatcher, error) {
-	return ms.Repository.GetRegexpMatchers(method)
+// Reset removes all mocks from the namespace.
+func (ms MockService) Reset(namespace string) (int, error) {
+	return ms.Repository.DeleteBySelector(model.Selector{Namespace: namespace})
 }
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+type SessionInt interface {
+	Create(namespace string) (model.Session, error)
+	Get(id string) (model.Session, bool)
+	List(namespace string) []model.Session
+	Delete(id string) (model.SessionCleanup, error)
+}
+
+// SessionService tracks test sessions. Deleting a session removes its mocks and journal entries.
+type SessionService struct {
+	lock     sync.RWMutex
+	sessions map[string]model.Session
+	mocks    MockInt
+	journal  JournalInt
+}
+
+func InitSessionService(mocks MockInt, journal JournalInt) *SessionService {
+	return &SessionService{sessions: map[string]model.Session{}, mocks: mocks, journal: journal}
+}
+
+func (ss *SessionService) Create(namespace string) (model.Session, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return model.Session{}, err
+	}
+	session := model.Session{ID: hex.EncodeToString(id), Namespace: namespace, Created: time.Now()}
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	ss.sessions[session.ID] = session
+	return session, nil
+}
+
+func (ss *SessionService) Get(id string) (model.Session, bool) {
+	ss.lock.RLock()
+	defer ss.lock.RUnlock()
+	session, ok := ss.sessions[id]
+	return session, ok
+}
+
+func (ss *SessionService) List(namespace string) []model.Session {
+	ss.lock.RLock()
+	defer ss.lock.RUnlock()
+	sessions := []model.Session{}
+	for _, session := range ss.sessions {
+		if session.Namespace == namespace {
+			sessions = append(sessions, session)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b model.Session) int { return a.Created.Compare(b.Created) })
+	return sessions
+}
+
+// Delete holds the session lock until the session is removed, so a session is never
+// listed or found once its mocks are deleted.
+func (ss *SessionService) Delete(id string) (model.SessionCleanup, error) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	session, ok := ss.sessions[id]
+	if !ok {
+		return model.SessionCleanup{}, errors.New(model.UnknownSession)
+	}
+	var cleanup model.SessionCleanup
+	var err error
+	cleanup.Mocks, err = ss.mocks.DeleteAll(model.Selector{Namespace: session.Namespace, SessionID: session.ID})
+	if err != nil {
+		return cleanup, err
+	}
+	cleanup.JournalEntries = ss.journal.Clear(model.JournalFilter{Namespace: session.Namespace, SessionID: session.ID})
+	delete(ss.sessions, id)
+	return cleanup, nil
+}
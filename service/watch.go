@@ -0,0 +1,168 @@
+package service
+
+import (
+	"log"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+)
+
+// ImportWatcher polls the import directory and applies added, changed and deleted mock files to the repository.
+// Mocks of a file that fails to import are kept until the file is fixed.
+type ImportWatcher struct {
+	service  MockService
+	interval time.Duration
+	lock     sync.Mutex
+	files    map[string]string
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// InitImportWatcher watches the import directory of service. Unless the directory was imported already,
+// its files are loaded first, as if they were added.
+func InitImportWatcher(service MockService, interval time.Duration, imported bool) *ImportWatcher {
+	iw := &ImportWatcher{service: service, interval: interval, files: map[string]string{}}
+	if imported {
+		files, err := util.SnapshotFiles(service.ImportDir)
+		if err != nil {
+			log.Println(err)
+		}
+		iw.files = files
+	} else if results, err := iw.Poll(); err != nil {
+		log.Println(err)
+	} else {
+		log.Printf("Imported mocks: %v", results)
+	}
+	return iw
+}
+
+// Start polls the import directory in the background until Stop is called.
+func (iw *ImportWatcher) Start() {
+	iw.stop, iw.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(iw.done)
+		ticker := time.NewTicker(iw.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-iw.stop:
+				return
+			case <-ticker.C:
+				if results, err := iw.Poll(); err != nil {
+					log.Println(err)
+				} else if len(results) != 0 {
+					log.Printf("Reloaded mocks: %v", results)
+				}
+			}
+		}
+	}()
+}
+
+func (iw *ImportWatcher) Stop() {
+	if iw.stop == nil {
+		return
+	}
+	close(iw.stop)
+	<-iw.done
+	iw.stop = nil
+}
+
+// Poll applies the files changed since the previous poll and returns the results of the reloaded files.
+// A changed defaults file reloads all mock files in its folder and subfolders.
+func (iw *ImportWatcher) Poll() ([]model.ImportResult, error) {
+	iw.lock.Lock()
+	defer iw.lock.Unlock()
+
+	files, err := util.SnapshotFiles(iw.service.ImportDir)
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	changed := changedSources(iw.files, files)
+	if len(changed) == 0 {
+		return []model.ImportResult{}, nil
+	}
+
+	importDir := filepath.Clean(iw.service.ImportDir)
+	results, err := util.Import(importDir, iw.service.importOptions(model.ImportOptions{}))
+	if err != nil {
+		return []model.ImportResult{}, err
+	}
+	results = iw.service.checkContract(results)
+	bySource := map[string][]model.ImportResult{}
+	failed := map[string]bool{}
+	for i := range results {
+		source := results[i].File
+		if results[i].Mock != nil {
+			source = results[i].Mock.Source
+		} else if rel, err := filepath.Rel(importDir, source); err == nil {
+			source = filepath.ToSlash(rel)
+		}
+		results[i].File = source
+		bySource[source] = append(bySource[source], results[i])
+		failed[source] = failed[source] || results[i].Status == model.ImportFailed
+	}
+	reloaded := []model.ImportResult{}
+	var failedDirs []string
+	for source := range failed {
+		if failed[source] && util.IsDefaultsFile(source) {
+			failedDirs = append(failedDirs, path.Dir(source))
+			reloaded = append(reloaded, bySource[source]...)
+		}
+	}
+	for _, source := range changed {
+		if failed[source] || inDirs(source, failedDirs) {
+			log.Printf("Keeping mocks of [%s] - failed to import", source)
+			reloaded = append(reloaded, bySource[source]...)
+			continue
+		}
+		applied, err := iw.service.Repository.ReplaceSource(source, bySource[source])
+		if err != nil {
+			return reloaded, err
+		}
+		for i := range applied {
+			applied[i].File = source
+		}
+		reloaded = append(reloaded, applied...)
+	}
+	iw.files = files
+	return reloaded, nil
+}
+
+// changedSources lists mock files added, changed or removed between two snapshots.
+func changedSources(previous map[string]string, current map[string]string) []string {
+	changed := map[string]bool{}
+	var dirs []string
+	for _, files := range [][2]map[string]string{{previous, current}, {current, previous}} {
+		for file, hash := range files[0] {
+			if files[1][file] == hash {
+				continue
+			}
+			if util.IsDefaultsFile(file) {
+				dirs = append(dirs, path.Dir(file))
+			} else {
+				changed[file] = true
+			}
+		}
+	}
+	for _, files := range []map[string]string{previous, current} {
+		for file := range files {
+			if !util.IsDefaultsFile(file) && inDirs(file, dirs) {
+				changed[file] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(changed))
+}
+
+func inDirs(file string, dirs []string) bool {
+	return slices.ContainsFunc(dirs, func(dir string) bool {
+		return dir == "." || strings.HasPrefix(file, dir+"/")
+	})
+}
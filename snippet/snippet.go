@@ -0,0 +1,314 @@
+// Package snippet
+package snippet
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const (
+	FormatCurl = "curl"
+	FormatGo   = "go"
+
+	UnsupportedBodyMatcher = "Body matcher can not be turned into a request body"
+	UnmatchedExamplePath   = "Example path does not match the regex path"
+	ClientCertRequired     = "Request must be sent over HTTPS with a client certificate matching"
+)
+
+// Snippet is a request matching a mock, rendered as curl command and Go code.
+type Snippet struct {
+	MockID  int64             `json:"mockId"`
+	Name    string            `json:"name,omitempty"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Curl    string            `json:"curl"`
+	Go      string            `json:"go"`
+	// Notes list matchers the request could not be derived from.
+	Notes []string `json:"notes,omitempty"`
+}
+
+type Options struct {
+	BaseURL string
+	// NamespaceHeader selects the namespace of mocks outside the default namespace.
+	NamespaceHeader string
+}
+
+type header struct {
+	name  string
+	value string
+}
+
+// For renders a request matching the method, path and matchers of mock.
+// Regex paths are replaced with an example path matching the regex.
+func For(mock model.Mock, options Options) Snippet {
+	snippet := Snippet{MockID: mock.ID, Name: mock.Name, Method: mock.Method}
+	path := mock.Path
+	if len(mock.RegexPath) != 0 {
+		path = ExamplePath(mock.RegexPath)
+		if pattern := model.RegexPath(mock.RegexPath).Compile(); pattern == nil || !pattern.MatchString(path) {
+			snippet.Notes = append(snippet.Notes, fmt.Sprintf("%s [%s]", UnmatchedExamplePath, mock.RegexPath))
+		}
+	}
+	snippet.URL = strings.TrimSuffix(cmp.Or(options.BaseURL, "http://localhost:8080"), "/") + path
+	if len(mock.RequestQueryMatchers) != 0 {
+		query := url.Values{}
+		for _, matcher := range mock.RequestQueryMatchers {
+			query.Set(matcher.Key, fmt.Sprint(matcher.Value))
+		}
+		snippet.URL += "?" + query.Encode()
+	}
+
+	var headers []header
+	if len(mock.Namespace) != 0 {
+		headers = append(headers, header{cmp.Or(options.NamespaceHeader, model.DefaultNamespaceHeader), mock.Namespace})
+	}
+	if len(mock.SessionID) != 0 {
+		headers = append(headers, header{model.SessionHeader, mock.SessionID})
+	}
+	for _, matcher := range mock.RequestHeaderMatchers {
+		headers = append(headers, header{http.CanonicalHeaderKey(matcher.Key), fmt.Sprint(matcher.Value)})
+	}
+	var body string
+	if len(mock.RequestBodyMatchers) != 0 {
+		value, notes := Body(mock.RequestBodyMatchers)
+		snippet.Notes = append(snippet.Notes, notes...)
+		data, _ := json.Marshal(value)
+		body = string(data)
+		headers = append(headers, header{"Content-Type", "application/json"})
+	}
+
+	if len(mock.RequestCertMatchers) != 0 {
+		var matchers []string
+		for _, matcher := range mock.RequestCertMatchers {
+			matchers = append(matchers, fmt.Sprintf("%s=%v", matcher.Key, matcher.Value))
+		}
+		snippet.Notes = append(snippet.Notes, fmt.Sprintf("%s [%s]", ClientCertRequired, strings.Join(matchers, ", ")))
+	}
+
+	snippet.Body = body
+	for _, header := range headers {
+		if snippet.Headers == nil {
+			snippet.Headers = map[string]string{}
+		}
+		snippet.Headers[header.name] = header.value
+	}
+	snippet.Curl = curl(snippet, headers, body)
+	snippet.Go = goCode(snippet, headers, body)
+	return snippet
+}
+
+// Text renders snippets in format, each preceded by a comment naming its mock and notes.
+func Text(snippets []Snippet, format string) string {
+	var b strings.Builder
+	comment := "#"
+	if format == FormatGo {
+		comment = "//"
+	}
+	for i, snippet := range snippets {
+		if i != 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s %d %s %s\n", comment, snippet.MockID, snippet.Method, cmp.Or(snippet.Name, snippet.URL))
+		for _, note := range snippet.Notes {
+			fmt.Fprintf(&b, "%s %s\n", comment, note)
+		}
+		if format == FormatGo {
+			b.WriteString(snippet.Go)
+		} else {
+			b.WriteString(snippet.Curl + "\n")
+		}
+	}
+	return b.String()
+}
+
+func curl(snippet Snippet, headers []header, body string) string {
+	parts := []string{"curl", "-X", snippet.Method, shellQuote(snippet.URL)}
+	for _, header := range headers {
+		parts = append(parts, "-H", shellQuote(header.name+": "+header.value))
+	}
+	if len(body) != 0 {
+		parts = append(parts, "-d", shellQuote(body))
+	}
+	return strings.Join(parts, " ")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func goCode(snippet Snippet, headers []header, body string) string {
+	var b strings.Builder
+	reader := "nil"
+	if len(body) != 0 {
+		reader = "strings.NewReader(" + goString(body) + ")"
+	}
+	fmt.Fprintf(&b, "req, err := http.NewRequest(%q, %q, %s)\n", snippet.Method, snippet.URL, reader)
+	b.WriteString("if err != nil {\n\tt.Fatal(err)\n}\n")
+	for _, header := range headers {
+		fmt.Fprintf(&b, "req.Header.Set(%q, %q)\n", header.name, header.value)
+	}
+	b.WriteString("resp, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("if err != nil {\n\tt.Fatal(err)\n}\n")
+	b.WriteString("defer resp.Body.Close()\n")
+	return b.String()
+}
+
+// goString prefers raw string literals for JSON bodies.
+func goString(value string) string {
+	if strings.Contains(value, "`") {
+		return strconv.Quote(value)
+	}
+	return "`" + value + "`"
+}
+
+// Body builds a JSON body from matchers with simple JsonPath keys ('$.a.b', "$['a']", '$.a[0]').
+// Values are strings, as matchers compare the text of the selected values.
+func Body(matchers model.Matchers) (any, []string) {
+	var body any
+	var notes []string
+	for _, matcher := range matchers {
+		segments, ok := parsePath(matcher.Key)
+		if !ok {
+			notes = append(notes, fmt.Sprintf("%s [%s]", UnsupportedBodyMatcher, matcher.Key))
+			continue
+		}
+		if body, ok = set(body, segments, fmt.Sprint(matcher.Value)); !ok {
+			notes = append(notes, fmt.Sprintf("%s [%s]", UnsupportedBodyMatcher, matcher.Key))
+		}
+	}
+	if body == nil {
+		body = map[string]any{}
+	}
+	return body, notes
+}
+
+var pathSegment = regexp.MustCompile(`^(?:\.([A-Za-z_][A-Za-z0-9_-]*)|\['((?:[^'\\]|\\.)*)'\]|\["((?:[^"\\]|\\.)*)"\]|\[([0-9]+)\])`)
+
+// parsePath splits a JsonPath into names (string) and indexes (int). Filters and wildcards are not supported.
+func parsePath(path string) ([]any, bool) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, false
+	}
+	rest := path[1:]
+	var segments []any
+	for len(rest) != 0 {
+		match := pathSegment.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, false
+		}
+		switch {
+		case len(match[1]) != 0:
+			segments = append(segments, match[1])
+		case len(match[4]) != 0:
+			index, _ := strconv.Atoi(match[4])
+			segments = append(segments, index)
+		default:
+			segments = append(segments, strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`).Replace(match[2]+match[3]))
+		}
+		rest = rest[len(match[0]):]
+	}
+	return segments, len(segments) != 0
+}
+
+// set stores value at the path of segments, creating objects and arrays on the way.
+func set(node any, segments []any, value string) (any, bool) {
+	if len(segments) == 0 {
+		return value, node == nil
+	}
+	switch segment := segments[0].(type) {
+	case string:
+		if node == nil {
+			node = map[string]any{}
+		}
+		object, ok := node.(map[string]any)
+		if !ok {
+			return node, false
+		}
+		child, ok := set(object[segment], segments[1:], value)
+		object[segment] = child
+		return object, ok
+	case int:
+		if node == nil {
+			node = []any{}
+		}
+		array, ok := node.([]any)
+		if !ok || segment > 100 {
+			return node, false
+		}
+		for len(array) <= segment {
+			array = append(array, nil)
+		}
+		child, ok := set(array[segment], segments[1:], value)
+		array[segment] = child
+		return array, ok
+	}
+	return node, false
+}
+
+// ExamplePath generates a path matching a regex path, e.g. '/users/a' for '^/users/[^/]+$'.
+func ExamplePath(regexPath string) string {
+	re, err := syntax.Parse(regexPath, syntax.Perl)
+	if err != nil {
+		return regexPath
+	}
+	var b strings.Builder
+	example(re.Simplify(), &b)
+	path := b.String()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// exampleRunes are preferred when a character class allows them.
+var exampleRunes = []rune("a1x_-.")
+
+func example(re *syntax.Regexp, b *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		example(re.Sub[0], b)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			example(sub, b)
+		}
+	case syntax.OpAlternate:
+		example(re.Sub[0], b)
+	case syntax.OpPlus:
+		example(re.Sub[0], b)
+	case syntax.OpRepeat:
+		for range re.Min {
+			example(re.Sub[0], b)
+		}
+	}
+}
+
+func classRune(ranges []rune) rune {
+	for _, r := range exampleRunes {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return 'a'
+	}
+	return ranges[0]
+}
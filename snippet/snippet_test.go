@@ -0,0 +1,79 @@
+package snippet
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+func TestFor(t *testing.T) {
+	mock := model.Mock{
+		ID:                    7,
+		Name:                  "find person",
+		Namespace:             "billing",
+		Method:                "POST",
+		RegexPath:             "^/person/[0-9]+/find$",
+		RequestQueryMatchers:  model.Matchers{{Key: "verbose", Value: "true"}},
+		RequestHeaderMatchers: model.Matchers{{Key: "x-client", Value: "tests"}},
+		RequestBodyMatchers: model.Matchers{
+			{Key: "$.name", Value: "O'Brien"},
+			{Key: "$.address.city", Value: "Warsaw"},
+			{Key: "$['first name']", Value: "John"},
+			{Key: "$.tags[1]", Value: "b"},
+			{Key: "$..id", Value: "1"},
+		},
+		ResponseStatus: 200,
+	}
+
+	snippet := For(mock, Options{BaseURL: "http://localhost:8080/"})
+
+	assert.Equal(t, "http://localhost:8080/person/1/find?verbose=true", snippet.URL)
+	assert.Equal(t, map[string]string{"X-Mockery-Namespace": "billing", "X-Client": "tests", "Content-Type": "application/json"}, snippet.Headers)
+	assert.JSONEq(t, `{"name": "O'Brien", "address": {"city": "Warsaw"}, "first name": "John", "tags": [null, "b"]}`, snippet.Body)
+	assert.Equal(t, []string{UnsupportedBodyMatcher + " [$..id]"}, snippet.Notes)
+	assert.Equal(t, `curl -X POST 'http://localhost:8080/person/1/find?verbose=true' -H 'X-Mockery-Namespace: billing' -H 'X-Client: tests' `+
+		`-H 'Content-Type: application/json' -d '`+strings.ReplaceAll(snippet.Body, "'", `'\''`)+`'`, snippet.Curl)
+	assert.Contains(t, snippet.Go, "req, err := http.NewRequest(\"POST\", \"http://localhost:8080/person/1/find?verbose=true\", strings.NewReader(`")
+	assert.Contains(t, snippet.Go, `req.Header.Set("X-Client", "tests")`)
+
+	mock.RequestBodyMatchers = mock.RequestBodyMatchers[:4]
+	snippet = For(mock, Options{})
+	requestURL, _ := url.Parse(snippet.URL)
+	header := http.Header{}
+	for name, value := range snippet.Headers {
+		header.Set(name, value)
+	}
+	assert.True(t, mock.MatchesRoute(snippet.Method, requestURL.Path))
+	assert.True(t, mock.MatchesRequest(requestURL.Query(), header, []byte(snippet.Body)))
+}
+
+func TestExamplePath(t *testing.T) {
+	tests := []struct {
+		regexPath string
+		expected  string
+	}{
+		{"^/users/[^/]+$", "/users/a"},
+		{"^/users/[0-9]{3}/orders/(open|closed)$", "/users/111/orders/open"},
+		{"/files/.*", "/files/"},
+		{"^/v[12]/items/\\d+$", "/v1/items/1"},
+		{"users", "/users"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, ExamplePath(tt.regexPath), tt.regexPath)
+	}
+}
+
+func TestText(t *testing.T) {
+	snippets := []Snippet{
+		{MockID: 1, Method: "GET", URL: "http://localhost:8080/a", Curl: "curl -X GET 'http://localhost:8080/a'", Go: "go code\n"},
+		{MockID: 2, Name: "b", Method: "GET", Curl: "curl b", Notes: []string{"note"}},
+	}
+
+	assert.Equal(t, "# 1 GET http://localhost:8080/a\ncurl -X GET 'http://localhost:8080/a'\n\n# 2 GET b\n# note\ncurl b\n", Text(snippets, FormatCurl))
+	assert.Equal(t, "// 1 GET http://localhost:8080/a\ngo code\n", Text(snippets[:1], FormatGo))
+}
@@ -0,0 +1,181 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const (
+	ArchiveZip   = "zip"
+	ArchiveTarGz = "tar.gz"
+
+	// MaxUploadSize limits uploaded files and the total size of extracted archives.
+	MaxUploadSize int64 = 32 << 20
+)
+
+var (
+	ErrInvalidArchive    = errors.New("Invalid archive")
+	ErrUnsupportedFormat = errors.New(model.UnsupportedFormat)
+)
+
+func ArchiveOf(name string) string {
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz
+	}
+	return ""
+}
+
+// WriteFiles stores uploaded mock files and the contents of uploaded archives in dir, keeping their folder structure.
+func WriteFiles(dir string, files []model.MockFile) error {
+	var total int64
+	for _, file := range files {
+		var err error
+		switch ArchiveOf(file.Name) {
+		case ArchiveZip:
+			err = extractZip(dir, file.Contents, &total)
+		case ArchiveTarGz:
+			err = extractTarGz(dir, file.Contents, &total)
+		default:
+			if !IsMockFile(file.Name) {
+				return fmt.Errorf("%w [%s]", ErrUnsupportedFormat, file.Name)
+			}
+			err = writeEntry(dir, filepath.Base(file.Name), bytes.NewReader(file.Contents), &total)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZip(dir string, contents []byte, total *int64) error {
+	reader, err := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		return fmt.Errorf("%w. %s", ErrInvalidArchive, err.Error())
+	}
+	for _, entry := range reader.File {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		file, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("%w. %s", ErrInvalidArchive, err.Error())
+		}
+		err = writeEntry(dir, entry.Name, file, total)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTarGz(dir string, contents []byte, total *int64) error {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return fmt.Errorf("%w. %s", ErrInvalidArchive, err.Error())
+	}
+	defer gzipReader.Close()
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w. %s", ErrInvalidArchive, err.Error())
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err = writeEntry(dir, header.Name, reader, total); err != nil {
+			return err
+		}
+	}
+}
+
+// writeEntry writes mock and defaults files, other files are skipped.
+// Entries outside of dir are rejected.
+func writeEntry(dir string, name string, contents io.Reader, total *int64) error {
+	name = filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("%w. Entry outside of archive [%s]", ErrInvalidArchive, name)
+	}
+	if !IsMockFile(name) {
+		return nil
+	}
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	written, err := io.Copy(file, io.LimitReader(contents, MaxUploadSize-*total+1))
+	*total += written
+	if err != nil {
+		return err
+	}
+	if *total > MaxUploadSize {
+		return fmt.Errorf("%w. Extracted files exceed %d bytes", ErrInvalidArchive, MaxUploadSize)
+	}
+	return nil
+}
+
+// WriteArchive writes mocks as an archive of files named like the exported ones.
+func WriteArchive(w io.Writer, archive string, format string, mocks []model.Mock) error {
+	switch archive {
+	case ArchiveZip:
+		writer := zip.NewWriter(w)
+		for i := range mocks {
+			contents, err := marshalMock(mocks[i], format)
+			if err != nil {
+				return err
+			}
+			file, err := writer.Create(ExportFileName(mocks[i], format))
+			if err != nil {
+				return err
+			}
+			if _, err = file.Write(contents); err != nil {
+				return err
+			}
+		}
+		return writer.Close()
+	case ArchiveTarGz:
+		gzipWriter := gzip.NewWriter(w)
+		writer := tar.NewWriter(gzipWriter)
+		for i := range mocks {
+			contents, err := marshalMock(mocks[i], format)
+			if err != nil {
+				return err
+			}
+			header := &tar.Header{Name: ExportFileName(mocks[i], format), Mode: 0o644, Size: int64(len(contents))}
+			if err = writer.WriteHeader(header); err != nil {
+				return err
+			}
+			if _, err = writer.Write(contents); err != nil {
+				return err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		return gzipWriter.Close()
+	}
+	return fmt.Errorf("%w [%s]", ErrUnsupportedFormat, archive)
+}
@@ -2,33 +2,31 @@
 package util
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/rromanowicz/mockery/model"
 )
 
-func Export(exportDir string, mocks []model.Mock) ([]string, error) {
+// Export writes every mock to its own file in the given format (json or yaml).
+func Export(exportDir string, mocks []model.Mock, format string) ([]string, error) {
 	os.MkdirAll(exportDir, os.ModePerm)
 	var files []string
 	for i := range mocks {
-		mockData, err := json.Marshal(mocks[i])
+		mockData, err := marshalMock(mocks[i], format)
 		if err != nil {
 			log.Printf("Failed to marshal mock [id=%v]. Error: %v", mocks[i].ID, err.Error())
 			continue
 		}
-		var urlPath string
-		if len(mocks[i].Path) != 0 {
-			urlPath = strings.ReplaceAll(mocks[i].Path, "/", "_")
-		} else {
-			urlPath = strings.ReplaceAll(strings.ReplaceAll(mocks[i].RegexPath, "/", "_"), "\\", "")
-		}
-		fileName := fmt.Sprintf("%s/%v_%s%s.json", exportDir, mocks[i].ID, mocks[i].Method, urlPath)
+		fileName := fmt.Sprintf("%s/%s", exportDir, ExportFileName(mocks[i], format))
 		err = writeFile(fileName, mockData)
 		if err != nil {
 			log.Printf("Failed to write mock [%s]. Error: %v", fileName, err.Error())
@@ -39,53 +37,182 @@ func Export(exportDir string, mocks []model.Mock) ([]string, error) {
 	return files, nil
 }
 
-func Import(importDir string) ([]model.Mock, []string, error) {
-	files, err := listJSONFiles(importDir)
-	if err != nil {
-		return []model.Mock{}, []string{}, err
+func ExportFileName(mock model.Mock, format string) string {
+	var urlPath string
+	if len(mock.Path) != 0 {
+		urlPath = strings.ReplaceAll(mock.Path, "/", "_")
+	} else {
+		urlPath = strings.ReplaceAll(strings.ReplaceAll(mock.RegexPath, "/", "_"), "\\", "")
 	}
-	var mocks []model.Mock
-	var importedFiles []string
-	for i := range files {
-		contents, err := readFile(files[i])
+	if format != model.FormatYAML {
+		format = model.FormatJSON
+	}
+	return fmt.Sprintf("%v_%s%s.%s", mock.ID, mock.Method, urlPath, format)
+}
+
+// Import reads mocks from importDir and its subfolders. Folder defaults are applied
+// before validation, the folder itself is turned into a tag or namespace according to options.
+// Successfully read mocks are attached to their results, with the file path relative to importDir as their source.
+func Import(importDir string, options model.ImportOptions) ([]model.ImportResult, error) {
+	results := []model.ImportResult{}
+	defaults := map[string]model.ImportDefaults{}
+	importDir = filepath.Clean(importDir)
+	err := filepath.WalkDir(importDir, func(file string, entry fs.DirEntry, err error) error {
+		if file == importDir && errors.Is(err, fs.ErrNotExist) {
+			return fs.SkipAll
+		}
 		if err != nil {
-			log.Printf("Failed to read [%v]. Error: %v", files[i], err.Error())
-			continue
+			return err
 		}
-		var mock model.Mock
-		err = json.Unmarshal(contents, &mock)
+		if entry.IsDir() {
+			folderDefaults, result := readDefaults(importDir, file, options.Folders)
+			defaults[file] = defaults[filepath.Dir(file)].Merge(folderDefaults)
+			if result != nil {
+				results = append(results, *result)
+			}
+			return nil
+		}
+		if isDefaultsFile(entry.Name()) || !IsMockFile(entry.Name()) {
+			return nil
+		}
+		contents, err := readFile(file)
 		if err != nil {
-			log.Printf("Failed to parse [%v], Error: %v", files[i], err.Error())
+			results = append(results, failedImport(file, 0, fmt.Sprintf("Failed to read file. %s", err.Error())))
+			return nil
+		}
+		source, _ := filepath.Rel(importDir, file)
+		fileResults := ReadMocks(file, contents, defaults[filepath.Dir(file)])
+		for i := range fileResults {
+			if fileResults[i].Mock != nil {
+				fileResults[i].Mock.Source = path.Join(options.Dir, filepath.ToSlash(source))
+			}
+		}
+		results = append(results, fileResults...)
+		return nil
+	})
+	if err != nil {
+		log.Println(err.Error())
+		return []model.ImportResult{}, err
+	}
+	return results, nil
+}
+
+// ReadMocks parses and validates mocks of a single file. The file format is selected by its extension.
+func ReadMocks(file string, contents []byte, defaults model.ImportDefaults) []model.ImportResult {
+	mocks, err := parseMocks(file, contents)
+	if err != nil {
+		result := failedImport(file, 0, fmt.Sprintf("Failed to parse file. %s", err.Error()))
+		log.Printf("Import failed for file[%s]. Errors [%v]", file, result.Errors)
+		return []model.ImportResult{result}
+	}
+	var results []model.ImportResult
+	for i := range mocks {
+		mock := mocks[i].mock
+		if mocks[i].err != nil {
+			results = append(results, failedImport(file, mocks[i].line, fmt.Sprintf("Failed to parse mock. %s", mocks[i].err.Error())))
 			continue
 		}
-		ok, errors := mock.Validate()
-		var importStatus string
-		if ok {
-			mocks = append(mocks, mock)
-			importStatus = "[OK]"
-		} else {
-			log.Printf("Import failed for file[%s]. Found validation errors [%v]", files[i], errors)
-			importStatus = "[FAILED]"
+		defaults.Apply(&mock)
+		if ok, errors := mock.Validate(); !ok {
+			results = append(results, failedImport(file, mocks[i].line, errors...))
+			continue
+		}
+		results = append(results, model.ImportResult{File: file, Line: mocks[i].line, Status: model.ImportOK, Mock: &mock})
+	}
+	for _, result := range results {
+		if result.Status != model.ImportOK {
+			log.Printf("Import failed for file[%s]. Errors [%v]", file, result.Errors)
 		}
-		importedFiles = append(importedFiles, fmt.Sprintf("%-8s %s", importStatus, files[i]))
 	}
+	return results
+}
 
-	return mocks, importedFiles, nil
+// readDefaults reads the defaults file of dir and adds the folder tag or namespace.
+// Result is only returned when the defaults file could not be processed.
+func readDefaults(importDir string, dir string, folders string) (model.ImportDefaults, *model.ImportResult) {
+	var defaults model.ImportDefaults
+	if dir != importDir {
+		switch folders {
+		case model.FoldersAsNamespace:
+			if filepath.Dir(dir) == importDir {
+				defaults.Namespace = filepath.Base(dir)
+			}
+		case model.FoldersIgnored:
+		default:
+			defaults.Tags = model.Tags{filepath.Base(dir)}
+		}
+	}
+
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		file := filepath.Join(dir, model.DefaultsFile+ext)
+		contents, err := readFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			result := failedImport(file, 0, fmt.Sprintf("Failed to read file. %s", err.Error()))
+			return defaults, &result
+		}
+		fileDefaults, err := parseDefaults(file, contents)
+		if err != nil {
+			result := failedImport(file, 0, fmt.Sprintf("Failed to parse file. %s", err.Error()))
+			return defaults, &result
+		}
+		return defaults.Merge(fileDefaults), nil
+	}
+	return defaults, nil
 }
 
-func listJSONFiles(dir string) ([]string, error) {
-	root := os.DirFS(dir)
-	mdFiles, err := fs.Glob(root, "*.json")
-	if err != nil {
-		log.Println(err.Error())
-		return []string{}, err
+// failedImport reports errors of a file, or of a single mock in it when line is known.
+func failedImport(file string, line int, errors ...string) model.ImportResult {
+	if line != 0 {
+		for i := range errors {
+			errors[i] = fmt.Sprintf("line %d: %s", line, errors[i])
+		}
 	}
+	return model.ImportResult{File: file, Line: line, Status: model.ImportFailed, Errors: errors}
+}
 
-	var files []string
-	for _, v := range mdFiles {
-		files = append(files, path.Join(dir, v))
+var ErrInvalidDirectory = errors.New(model.InvalidDirectory)
+
+// ResolveDir returns subDir within baseDir, or baseDir when subDir is empty.
+// Absolute paths and paths escaping baseDir are rejected.
+func ResolveDir(baseDir string, subDir string) (string, error) {
+	if len(subDir) == 0 {
+		return baseDir, nil
 	}
-	return files, nil
+	if !filepath.IsLocal(subDir) {
+		return "", fmt.Errorf("%w [%s]", ErrInvalidDirectory, subDir)
+	}
+	return filepath.Join(baseDir, subDir), nil
+}
+
+// SnapshotFiles hashes the contents of mock and defaults files in dir and its subfolders.
+// Keys are paths relative to dir.
+func SnapshotFiles(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	dir = filepath.Clean(dir)
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if file == dir && errors.Is(err, fs.ErrNotExist) {
+			return fs.SkipAll
+		}
+		if err != nil || entry.IsDir() || !IsMockFile(entry.Name()) {
+			return err
+		}
+		contents, err := readFile(file)
+		if err != nil {
+			return err
+		}
+		source, _ := filepath.Rel(dir, file)
+		hash := sha256.Sum256(contents)
+		files[filepath.ToSlash(source)] = hex.EncodeToString(hash[:])
+		return nil
+	})
+	return files, err
+}
+
+func IsDefaultsFile(name string) bool {
+	return isDefaultsFile(path.Base(name))
 }
 
 func readFile(path string) ([]byte, error) {
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+	"gopkg.in/yaml.v3"
+)
+
+// parsedMock is a mock read from a file. Line is the line of the mock definition, 0 for single mock JSON files.
+type parsedMock struct {
+	mock model.Mock
+	line int
+	err  error
+}
+
+func IsMockFile(name string) bool {
+	return len(formatOf(name)) != 0
+}
+
+func isDefaultsFile(name string) bool {
+	return strings.TrimSuffix(name, filepath.Ext(name)) == model.DefaultsFile
+}
+
+func formatOf(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return model.FormatJSON
+	case ".yaml", ".yml":
+		return model.FormatYAML
+	}
+	return ""
+}
+
+// parseMocks reads a single mock or a list of mocks. YAML files may also contain multiple documents.
+// Failure of a single mock of a list is reported in its parsedMock, failure of the whole file as error.
+func parseMocks(name string, contents []byte) ([]parsedMock, error) {
+	if formatOf(name) == model.FormatYAML {
+		return parseYAMLMocks(contents)
+	}
+	return parseJSONMocks(contents)
+}
+
+func parseJSONMocks(contents []byte) ([]parsedMock, error) {
+	trimmed := bytes.TrimSpace(contents)
+	if !bytes.HasPrefix(trimmed, []byte("[")) {
+		var mock model.Mock
+		if err := json.Unmarshal(contents, &mock); err != nil {
+			return nil, jsonError(contents, err)
+		}
+		return []parsedMock{{mock: mock}}, nil
+	}
+
+	var mocks []parsedMock
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	if _, err := decoder.Token(); err != nil {
+		return nil, jsonError(contents, err)
+	}
+	for decoder.More() {
+		line := lineAt(contents, decoder.InputOffset())
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, jsonError(contents, err)
+		}
+		var mock model.Mock
+		err := json.Unmarshal(raw, &mock)
+		mocks = append(mocks, parsedMock{mock: mock, line: line, err: err})
+	}
+	return mocks, nil
+}
+
+func parseYAMLMocks(contents []byte) ([]parsedMock, error) {
+	var mocks []parsedMock
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return mocks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		nodes := document.Content
+		if nodes[0].Kind == yaml.SequenceNode {
+			nodes = nodes[0].Content
+		}
+		for _, node := range nodes {
+			var mock model.Mock
+			err := node.Decode(&mock)
+			mocks = append(mocks, parsedMock{mock: mock, line: node.Line, err: err})
+		}
+	}
+}
+
+func parseDefaults(name string, contents []byte) (model.ImportDefaults, error) {
+	var defaults model.ImportDefaults
+	if formatOf(name) == model.FormatYAML {
+		return defaults, yaml.Unmarshal(contents, &defaults)
+	}
+	if err := json.Unmarshal(contents, &defaults); err != nil {
+		return defaults, jsonError(contents, err)
+	}
+	return defaults, nil
+}
+
+func marshalMock(mock model.Mock, format string) ([]byte, error) {
+	if format == model.FormatYAML {
+		return yaml.Marshal(mock)
+	}
+	return json.Marshal(mock)
+}
+
+// MarshalMocks writes mocks as a single list file, readable by Import.
+func MarshalMocks(mocks []model.Mock, format string) ([]byte, error) {
+	if format == model.FormatYAML {
+		return yaml.Marshal(mocks)
+	}
+	return json.MarshalIndent(mocks, "", "  ")
+}
+
+// FormatOf returns the mock file format of name, empty if it is not a mock file.
+func FormatOf(name string) string {
+	return formatOf(name)
+}
+
+// jsonError adds the line number to syntax errors.
+func jsonError(contents []byte, err error) error {
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) {
+		return fmt.Errorf("line %d: %w", lineAt(contents, syntaxError.Offset), err)
+	}
+	return err
+}
+
+func lineAt(contents []byte, offset int64) int {
+	offset = min(offset, int64(len(contents)))
+	line := bytes.Count(contents[:offset], []byte("\n")) + 1
+	// Offset may point before the whitespace and separator preceding the value.
+	for _, char := range contents[offset:] {
+		if char == '\n' {
+			line++
+		} else if char != ' ' && char != '\t' && char != '\r' && char != ',' {
+			break
+		}
+	}
+	return line
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+// ignoredHeaders are set by most clients and not turned into matchers of recorded requests.
+var ignoredHeaders = []string{"Accept", "Accept-Encoding", "Connection", "Content-Length", "Content-Type", "Cookie", "Host", "User-Agent"}
+
+var (
+	identifier   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	pathVariable = regexp.MustCompile(`^(:.+|\{.+\})$`)
+)
+
+func IsIgnoredHeader(name string) bool {
+	return slices.Contains(ignoredHeaders, http.CanonicalHeaderKey(name))
+}
+
+// BodyMatchers turns every scalar of a JSON body into a JsonPath matcher. Nil is returned for non-JSON bodies.
+func BodyMatchers(body []byte) model.Matchers {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+	var matchers model.Matchers
+	collectMatchers("$", value, &matchers)
+	return matchers
+}
+
+func collectMatchers(path string, value any, matchers *model.Matchers) {
+	switch node := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(node)) {
+			if identifier.MatchString(key) {
+				collectMatchers(path+"."+key, node[key], matchers)
+			} else {
+				collectMatchers(fmt.Sprintf("%s['%s']", path, strings.ReplaceAll(key, "'", `\'`)), node[key], matchers)
+			}
+		}
+	case []any:
+		for i := range node {
+			collectMatchers(fmt.Sprintf("%s[%d]", path, i), node[i], matchers)
+		}
+	case nil:
+	default:
+		*matchers = append(*matchers, model.Matcher{Key: path, Value: fmt.Sprint(node)})
+	}
+}
+
+// RequestPath returns the literal path, or a regex path if it contains variable segments
+// (':id', '{id}' or '{{id}}').
+func RequestPath(path string) (string, string) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	segments := strings.Split(path, "/")
+	variable := false
+	for i := range segments {
+		if pathVariable.MatchString(segments[i]) {
+			segments[i], variable = `[^/]+`, true
+		} else {
+			segments[i] = regexp.QuoteMeta(segments[i])
+		}
+	}
+	if !variable {
+		return path, ""
+	}
+	return "", "^" + strings.Join(segments, "/") + "$"
+}
+
+// ResponseBody parses a recorded JSON object response body. Empty bodies are returned as nil.
+func ResponseBody(body []byte) (model.JSONB, error) {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil, nil
+	}
+	var object model.JSONB
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, errors.New(model.UnsupportedResponseBody)
+	}
+	return object, nil
+}
@@ -0,0 +1,359 @@
+// Package wiremock
+package wiremock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rromanowicz/mockery/model"
+	"github.com/rromanowicz/mockery/util"
+)
+
+const (
+	// UnsupportedConstruct fails the import of a mapping, mockery can not match or respond the same way.
+	UnsupportedConstruct = "Unsupported WireMock construct"
+	// IgnoredConstruct is reported for parts of a mapping not affecting which requests are matched.
+	IgnoredConstruct = "Ignored WireMock construct"
+
+	// UnsupportedMetadata lists mock properties WireMock can not represent in exported mappings.
+	UnsupportedMetadata = "mockeryUnsupported"
+)
+
+var ErrInvalidMapping = errors.New("Invalid WireMock mapping")
+
+var (
+	mappingKeys  = []string{"id", "uuid", "name", "request", "response", "persistent", "metadata"}
+	scenarioKeys = []string{"scenarioName", "requiredScenarioState", "newScenarioState"}
+	requestKeys  = []string{"method", "url", "urlPath", "urlPattern", "urlPathPattern", "headers", "queryParameters", "bodyPatterns"}
+	responseKeys = []string{"status", "jsonBody", "body", "headers"}
+	// responseSources can not be turned into a JSON body.
+	responseSources = []string{"bodyFileName", "base64Body", "fault", "proxyBaseUrl"}
+)
+
+// Mappings is the stub mapping file format of WireMock.
+type Mappings struct {
+	Mappings []Mapping `json:"mappings"`
+}
+
+type Mapping struct {
+	Name     string         `json:"name,omitempty"`
+	Request  Request        `json:"request"`
+	Response Response       `json:"response"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type Request struct {
+	Method          string             `json:"method"`
+	URL             string             `json:"url,omitempty"`
+	URLPath         string             `json:"urlPath,omitempty"`
+	URLPattern      string             `json:"urlPattern,omitempty"`
+	URLPathPattern  string             `json:"urlPathPattern,omitempty"`
+	Headers         map[string]Pattern `json:"headers,omitempty"`
+	QueryParameters map[string]Pattern `json:"queryParameters,omitempty"`
+	BodyPatterns    []Pattern          `json:"bodyPatterns,omitempty"`
+}
+
+// Pattern is a WireMock matcher, e.g. {"equalTo": "application/json"}.
+type Pattern map[string]any
+
+type Response struct {
+	Status   int            `json:"status"`
+	JSONBody any            `json:"jsonBody,omitempty"`
+	Body     string         `json:"body,omitempty"`
+	Headers  map[string]any `json:"headers,omitempty"`
+}
+
+// conversion collects unsupported and ignored constructs of a mapping.
+type conversion struct {
+	errs     []string
+	warnings []string
+}
+
+func (c *conversion) unsupported(construct string) {
+	c.errs = append(c.errs, fmt.Sprintf("%s [%s]", UnsupportedConstruct, construct))
+}
+
+func (c *conversion) ignored(construct string) {
+	c.warnings = append(c.warnings, fmt.Sprintf("%s [%s]", IgnoredConstruct, construct))
+}
+
+// Read turns a mappings file, or a file with a single mapping, into mocks.
+// Mappings using request matchers or responses mockery does not support fail to import.
+func Read(file string, contents []byte) ([]model.ImportResult, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &raw); err != nil {
+		return []model.ImportResult{}, fmt.Errorf("%w. %s", ErrInvalidMapping, err.Error())
+	}
+	rawMappings := []json.RawMessage{contents}
+	if mappings, ok := raw["mappings"]; ok {
+		if err := json.Unmarshal(mappings, &rawMappings); err != nil {
+			return []model.ImportResult{}, fmt.Errorf("%w. %s", ErrInvalidMapping, err.Error())
+		}
+	} else if _, ok := raw["request"]; !ok {
+		return []model.ImportResult{}, fmt.Errorf("%w. %s", ErrInvalidMapping, "Neither 'mappings' nor 'request' found.")
+	}
+
+	results := []model.ImportResult{}
+	for i, rawMapping := range rawMappings {
+		result := readMapping(rawMapping)
+		result.File = file
+		if len(rawMappings) > 1 {
+			for j := range result.Errors {
+				result.Errors[j] = fmt.Sprintf("mappings[%d]: %s", i, result.Errors[j])
+			}
+			for j := range result.Warnings {
+				result.Warnings[j] = fmt.Sprintf("mappings[%d]: %s", i, result.Warnings[j])
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func readMapping(contents []byte) model.ImportResult {
+	var mapping Mapping
+	var keys map[string]json.RawMessage
+	var raw struct {
+		Request  map[string]json.RawMessage `json:"request"`
+		Response map[string]json.RawMessage `json:"response"`
+	}
+	if err := json.Unmarshal(contents, &mapping); err != nil {
+		return model.ImportResult{Status: model.ImportFailed, Errors: []string{err.Error()}}
+	}
+	json.Unmarshal(contents, &keys)
+	json.Unmarshal(contents, &raw)
+
+	var c conversion
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		if slices.Contains(scenarioKeys, key) {
+			c.unsupported(key)
+		} else if !slices.Contains(mappingKeys, key) {
+			c.ignored(key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(raw.Request)) {
+		if !slices.Contains(requestKeys, key) {
+			c.unsupported("request." + key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(raw.Response)) {
+		if slices.Contains(responseSources, key) {
+			c.unsupported("response." + key)
+		} else if !slices.Contains(responseKeys, key) {
+			c.ignored("response." + key)
+		}
+	}
+
+	mock := mapping.mock(&c)
+	if len(c.errs) == 0 {
+		if valid, errs := mock.Validate(); !valid {
+			c.errs = errs
+		}
+	}
+	if len(c.errs) != 0 {
+		return model.ImportResult{Status: model.ImportFailed, Errors: c.errs, Warnings: c.warnings}
+	}
+	return model.ImportResult{Status: model.ImportOK, Mock: &mock, Warnings: c.warnings}
+}
+
+func (m Mapping) mock(c *conversion) model.Mock {
+	mock := model.Mock{Name: m.Name, Method: strings.ToUpper(m.Request.Method), ResponseStatus: m.Response.Status}
+	if mock.Method == "ANY" {
+		c.unsupported("request.method ANY")
+	}
+	if mock.ResponseStatus == 0 {
+		mock.ResponseStatus = http.StatusOK
+	}
+	if description, ok := m.Metadata["description"].(string); ok {
+		mock.Description = description
+	}
+	if tags, ok := m.Metadata["tags"].([]any); ok {
+		for _, tag := range tags {
+			mock.Tags = append(mock.Tags, fmt.Sprint(tag))
+		}
+	}
+
+	switch {
+	case len(m.Request.URL) != 0:
+		requestURL, err := url.Parse(m.Request.URL)
+		if err != nil {
+			c.unsupported("request.url " + err.Error())
+			break
+		}
+		mock.Path = requestURL.Path
+		query := requestURL.Query()
+		for _, name := range slices.Sorted(maps.Keys(query)) {
+			mock.RequestQueryMatchers = append(mock.RequestQueryMatchers, model.Matcher{Key: name, Value: query.Get(name)})
+		}
+	case len(m.Request.URLPath) != 0:
+		mock.Path = m.Request.URLPath
+	case len(m.Request.URLPattern) != 0:
+		if strings.Contains(m.Request.URLPattern, `\?`) {
+			c.unsupported("request.urlPattern with query")
+		}
+		mock.RegexPath = anchored(m.Request.URLPattern)
+	case len(m.Request.URLPathPattern) != 0:
+		mock.RegexPath = anchored(m.Request.URLPathPattern)
+	}
+
+	mock.RequestHeaderMatchers = equalTo(c, "request.headers", m.Request.Headers)
+	mock.RequestQueryMatchers = append(mock.RequestQueryMatchers, equalTo(c, "request.queryParameters", m.Request.QueryParameters)...)
+	for i, pattern := range m.Request.BodyPatterns {
+		mock.RequestBodyMatchers = append(mock.RequestBodyMatchers, bodyMatchers(c, fmt.Sprintf("request.bodyPatterns[%d]", i), pattern)...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(m.Response.Headers)) {
+		if !strings.EqualFold(name, "Content-Type") || !strings.Contains(fmt.Sprint(m.Response.Headers[name]), "json") {
+			c.ignored("response.headers." + name)
+		}
+	}
+	switch body := m.Response.JSONBody.(type) {
+	case nil:
+		var err error
+		if mock.ResponseBody, err = util.ResponseBody([]byte(m.Response.Body)); err != nil {
+			c.unsupported("response.body " + err.Error())
+		}
+	case map[string]any:
+		mock.ResponseBody = body
+	default:
+		c.unsupported("response.jsonBody " + model.UnsupportedResponseBody)
+	}
+	return mock
+}
+
+// equalTo turns 'equalTo' patterns into matchers, other patterns are unsupported.
+func equalTo(c *conversion, at string, patterns map[string]Pattern) model.Matchers {
+	var matchers model.Matchers
+	for _, name := range slices.Sorted(maps.Keys(patterns)) {
+		value, ok := patterns[name]["equalTo"].(string)
+		if !ok || len(patterns[name]) != 1 {
+			for _, operator := range slices.Sorted(maps.Keys(patterns[name])) {
+				if operator != "equalTo" {
+					c.unsupported(fmt.Sprintf("%s.%s.%s", at, name, operator))
+				}
+			}
+			continue
+		}
+		matchers = append(matchers, model.Matcher{Key: name, Value: value})
+	}
+	return matchers
+}
+
+// bodyMatchers supports 'matchesJsonPath' with an 'equalTo' value and 'equalToJson',
+// which matches every value of the expected JSON.
+func bodyMatchers(c *conversion, at string, pattern Pattern) model.Matchers {
+	if expected, ok := pattern["equalToJson"]; ok {
+		body, isString := expected.(string)
+		if !isString {
+			data, _ := json.Marshal(expected)
+			body = string(data)
+		}
+		matchers := util.BodyMatchers([]byte(body))
+		if matchers == nil {
+			c.unsupported(at + ".equalToJson")
+		}
+		return matchers
+	}
+	if jsonPath, ok := pattern["matchesJsonPath"].(map[string]any); ok {
+		expression, _ := jsonPath["expression"].(string)
+		value, ok := jsonPath["equalTo"].(string)
+		if len(expression) != 0 && ok && len(jsonPath) == 2 {
+			return model.Matchers{{Key: expression, Value: value}}
+		}
+	}
+	for _, operator := range slices.Sorted(maps.Keys(pattern)) {
+		c.unsupported(fmt.Sprintf("%s.%s", at, operator))
+	}
+	return nil
+}
+
+// anchored turns a WireMock pattern, which has to match the whole url, into a regex path.
+func anchored(pattern string) string {
+	if strings.Contains(pattern, "|") {
+		return "^(?:" + pattern + ")$"
+	}
+	return "^" + strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$") + "$"
+}
+
+// FromMocks converts mocks into WireMock mappings. Tags and description are kept in the mapping metadata,
+// mock properties WireMock can not represent are listed in its 'mockeryUnsupported' metadata.
+func FromMocks(mocks []model.Mock) Mappings {
+	mappings := Mappings{Mappings: []Mapping{}}
+	for _, mock := range mocks {
+		mappings.Mappings = append(mappings.Mappings, FromMock(mock))
+	}
+	return mappings
+}
+
+func FromMock(mock model.Mock) Mapping {
+	mapping := Mapping{
+		Name:     mock.Name,
+		Request:  Request{Method: mock.Method, URLPath: mock.Path},
+		Response: Response{Status: mock.ResponseStatus, Headers: map[string]any{"Content-Type": "application/json"}},
+		Metadata: map[string]any{},
+	}
+	if len(mock.ResponseBody) != 0 {
+		mapping.Response.JSONBody = mock.ResponseBody
+	}
+	if len(mock.RegexPath) != 0 {
+		pattern := mock.RegexPath
+		if !strings.HasPrefix(pattern, "^") {
+			pattern = ".*" + pattern
+		}
+		if !strings.HasSuffix(pattern, "$") || strings.HasSuffix(pattern, `\$`) {
+			pattern += ".*"
+		}
+		mapping.Request.URLPathPattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+	}
+	mapping.Request.Headers = patterns(mock.RequestHeaderMatchers)
+	mapping.Request.QueryParameters = patterns(mock.RequestQueryMatchers)
+	for _, matcher := range mock.RequestBodyMatchers {
+		mapping.Request.BodyPatterns = append(mapping.Request.BodyPatterns, Pattern{
+			"matchesJsonPath": map[string]any{"expression": matcher.Key, "equalTo": fmt.Sprint(matcher.Value)},
+		})
+	}
+
+	if len(mock.Description) != 0 {
+		mapping.Metadata["description"] = mock.Description
+	}
+	if len(mock.Tags) != 0 {
+		mapping.Metadata["tags"] = mock.Tags
+	}
+	var unsupported []string
+	if mock.Disabled {
+		unsupported = append(unsupported, "disabled")
+	}
+	if len(mock.Namespace) != 0 {
+		unsupported = append(unsupported, "namespace: "+mock.Namespace)
+	}
+	if len(mock.SessionID) != 0 {
+		unsupported = append(unsupported, "sessionId: "+mock.SessionID)
+	}
+	for _, matcher := range mock.RequestCertMatchers {
+		unsupported = append(unsupported, fmt.Sprintf("requestCertMatchers: %s=%v", matcher.Key, matcher.Value))
+	}
+	if len(unsupported) != 0 {
+		mapping.Metadata[UnsupportedMetadata] = unsupported
+	}
+	if len(mapping.Metadata) == 0 {
+		mapping.Metadata = nil
+	}
+	return mapping
+}
+
+func patterns(matchers model.Matchers) map[string]Pattern {
+	if len(matchers) == 0 {
+		return nil
+	}
+	patterns := map[string]Pattern{}
+	for _, matcher := range matchers {
+		patterns[matcher.Key] = Pattern{"equalTo": fmt.Sprint(matcher.Value)}
+	}
+	return patterns
+}
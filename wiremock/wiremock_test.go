@@ -0,0 +1,134 @@
+package wiremock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rromanowicz/mockery/model"
+)
+
+const mappings = `{
+  "mappings": [
+    {
+      "name": "get-user",
+      "priority": 1,
+      "metadata": { "tags": ["users"], "description": "Single user" },
+      "request": {
+        "method": "GET",
+        "urlPathPattern": "/users/[0-9]+",
+        "headers": { "X-Tenant": { "equalTo": "acme" } },
+        "queryParameters": { "full": { "equalTo": "true" } }
+      },
+      "response": {
+        "status": 200,
+        "jsonBody": { "id": 1 },
+        "headers": { "Content-Type": "application/json", "X-Trace": "1" },
+        "fixedDelayMilliseconds": 100
+      }
+    },
+    {
+      "request": {
+        "method": "post",
+        "url": "/users?notify=true",
+        "bodyPatterns": [
+          { "matchesJsonPath": { "expression": "$.name", "equalTo": "Tom" } },
+          { "equalToJson": "{\"age\": 3}" }
+        ]
+      },
+      "response": { "status": 201, "body": "{\"id\": 2}" }
+    },
+    {
+      "scenarioName": "signup",
+      "request": {
+        "method": "ANY",
+        "urlPath": "/signup",
+        "cookies": { "session": { "equalTo": "1" } },
+        "headers": { "Accept": { "contains": "json" } },
+        "bodyPatterns": [{ "matchesJsonPath": "$.email" }]
+      },
+      "response": { "bodyFileName": "signup.json" }
+    }
+  ]
+}`
+
+func TestRead(t *testing.T) {
+	results, err := Read("users.json", []byte(mappings))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+	assert.Equal(t, []string{
+		"mappings[0]: Ignored WireMock construct [priority]",
+		"mappings[0]: Ignored WireMock construct [response.fixedDelayMilliseconds]",
+		"mappings[0]: Ignored WireMock construct [response.headers.X-Trace]",
+	}, results[0].Warnings)
+	user := results[0].Mock
+	assert.Equal(t, "get-user", user.Name)
+	assert.Equal(t, "Single user", user.Description)
+	assert.Equal(t, model.Tags{"users"}, user.Tags)
+	assert.Equal(t, "^/users/[0-9]+$", user.RegexPath)
+	assert.Equal(t, model.Matchers{{Key: "X-Tenant", Value: "acme"}}, user.RequestHeaderMatchers)
+	assert.Equal(t, model.Matchers{{Key: "full", Value: "true"}}, user.RequestQueryMatchers)
+	assert.Equal(t, model.JSONB{"id": float64(1)}, user.ResponseBody)
+
+	assert.Equal(t, model.ImportOK, results[1].Status, results[1].Errors)
+	create := results[1].Mock
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "/users", create.Path)
+	assert.Equal(t, model.Matchers{{Key: "notify", Value: "true"}}, create.RequestQueryMatchers)
+	assert.Equal(t, model.Matchers{{Key: "$.name", Value: "Tom"}, {Key: "$.age", Value: "3"}}, create.RequestBodyMatchers)
+	assert.Equal(t, 201, create.ResponseStatus)
+	assert.Equal(t, model.JSONB{"id": float64(2)}, create.ResponseBody)
+
+	assert.Equal(t, model.ImportFailed, results[2].Status)
+	assert.ElementsMatch(t, []string{
+		"mappings[2]: Unsupported WireMock construct [scenarioName]",
+		"mappings[2]: Unsupported WireMock construct [request.cookies]",
+		"mappings[2]: Unsupported WireMock construct [response.bodyFileName]",
+		"mappings[2]: Unsupported WireMock construct [request.method ANY]",
+		"mappings[2]: Unsupported WireMock construct [request.headers.Accept.contains]",
+		"mappings[2]: Unsupported WireMock construct [request.bodyPatterns[0].matchesJsonPath]",
+	}, results[2].Errors)
+}
+
+func TestRead_SingleMapping(t *testing.T) {
+	results, err := Read("ping.json", []byte(`{"request": {"method": "GET", "urlPath": "/ping"}, "response": {"status": 204}}`))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, model.ImportOK, results[0].Status, results[0].Errors)
+	assert.Equal(t, 204, results[0].Mock.ResponseStatus)
+	assert.Nil(t, results[0].Mock.ResponseBody)
+
+	_, err = Read("ping.json", []byte(`{"response": {}}`))
+	assert.ErrorIs(t, err, ErrInvalidMapping)
+}
+
+func TestFromMock(t *testing.T) {
+	mock := model.Mock{
+		Name:                  "get-user",
+		Namespace:             "billing",
+		Disabled:              true,
+		Tags:                  model.Tags{"users"},
+		Method:                "GET",
+		RegexPath:             "^/users/[0-9]+$",
+		RequestHeaderMatchers: model.Matchers{{Key: "X-Tenant", Value: "acme"}},
+		RequestBodyMatchers:   model.Matchers{{Key: "$.name", Value: "Tom"}},
+		ResponseStatus:        200,
+		ResponseBody:          model.JSONB{"id": 1},
+	}
+
+	mapping := FromMock(mock)
+
+	assert.Equal(t, "/users/[0-9]+", mapping.Request.URLPathPattern)
+	assert.Equal(t, map[string]Pattern{"X-Tenant": {"equalTo": "acme"}}, mapping.Request.Headers)
+	assert.Equal(t, []Pattern{{"matchesJsonPath": map[string]any{"expression": "$.name", "equalTo": "Tom"}}}, mapping.Request.BodyPatterns)
+	assert.Equal(t, model.JSONB{"id": 1}, mapping.Response.JSONBody)
+	assert.Equal(t, model.Tags{"users"}, mapping.Metadata["tags"])
+	assert.Equal(t, []string{"disabled", "namespace: billing"}, mapping.Metadata[UnsupportedMetadata])
+
+	mock.RegexPath = "/users/"
+	assert.Equal(t, ".*/users/.*", FromMock(mock).Request.URLPathPattern)
+}